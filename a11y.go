@@ -0,0 +1,66 @@
+package anchor
+
+import (
+	"github.com/yuin/goldmark/util"
+)
+
+const (
+	_defaultLabelPrefix     = "Permalink to "
+	_defaultHiddenTextClass = "visually-hidden"
+)
+
+// Accessibility configures the markup that [Renderer] generates
+// for assistive technologies like screen readers.
+//
+// With Accessibility enabled, an anchor for the header "Foo"
+// is rendered as:
+//
+//	<a class="anchor" href="#foo" aria-label="Permalink to Foo"><span aria-hidden="true">¶</span></a>
+//
+// Screen readers will announce the anchor as "link, Permalink to Foo"
+// instead of reading out the anchor text.
+type Accessibility struct {
+	// LabelPrefix is prepended to the heading text
+	// to build the accessible name of the anchor.
+	//
+	// Defaults to "Permalink to ".
+	LabelPrefix string
+
+	// HiddenText specifies whether the accessible name
+	// should be included inside the anchor as visually hidden text
+	// instead of in an 'aria-label' attribute.
+	//
+	//	<a class="anchor" href="#foo"><span aria-hidden="true">¶</span><span class="visually-hidden">Permalink to Foo</span></a>
+	//
+	// The stylesheet must hide elements with HiddenTextClass visually
+	// while leaving them available to screen readers.
+	HiddenText bool
+
+	// HiddenTextClass is the class attached to the visually hidden text
+	// if HiddenText is set.
+	//
+	// Defaults to "visually-hidden".
+	HiddenTextClass string
+}
+
+// label returns the accessible name for an anchor
+// to a heading with the given text.
+// The returned value is HTML-escaped.
+func (a *Accessibility) label(text []byte) []byte {
+	prefix := a.LabelPrefix
+	if len(prefix) == 0 {
+		prefix = _defaultLabelPrefix
+	}
+
+	label := make([]byte, 0, len(prefix)+len(text))
+	label = append(label, prefix...)
+	label = append(label, text...)
+	return util.EscapeHTML(label)
+}
+
+func (a *Accessibility) hiddenTextClass() string {
+	if len(a.HiddenTextClass) == 0 {
+		return _defaultHiddenTextClass
+	}
+	return a.HiddenTextClass
+}
@@ -0,0 +1,457 @@
+// Package anchortest runs golden tests for goldmark-anchor
+// from YAML files.
+//
+// Use it to test custom [anchor.Texter] and [anchor.Attributer]
+// implementations with the same test format as goldmark-anchor itself.
+//
+//	func TestAnchors(t *testing.T) {
+//		suite := anchortest.Suite{
+//			Path: "testdata/anchors.yaml",
+//			Extender: func() *anchor.Extender {
+//				return &anchor.Extender{Texter: myTexter{}}
+//			},
+//		}
+//		suite.Run(t)
+//	}
+//
+// The YAML file holds a list of test cases.
+// Each case converts the Markdown in "give"
+// and compares the HTML output with "want".
+// Other keys of the case configure the [anchor.Extender]
+// (see [Options]).
+//
+//	# testdata/anchors.yaml
+//	- desc: before
+//	  pos: before
+//	  give: |
+//	    # Foo
+//	  want: |
+//	    <h1 id="foo"><a class="anchor" href="#foo">¶</a> Foo</h1>
+//
+// Run the tests with the -anchortest.update flag to rewrite the "want" fields
+// of the YAML file with the actual output.
+//
+//	go test -run TestAnchors -anchortest.update
+//
+// If the test package defines its own -update boolean flag,
+// that works too.
+package anchortest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"go.abhg.dev/goldmark/anchor"
+	"gopkg.in/yaml.v3"
+)
+
+// The flag is namespaced so that it doesn't conflict
+// with an -update flag defined by the test package.
+var _update = flag.Bool("anchortest.update", false, "rewrite the expected output of anchortest golden tests")
+
+// updateFlag reports whether the -anchortest.update flag is set,
+// or the -update flag if the test package defines one.
+func updateFlag() bool {
+	if *_update {
+		return true
+	}
+	f := flag.Lookup("update")
+	if f == nil {
+		return false
+	}
+	getter, ok := f.Value.(flag.Getter)
+	if !ok {
+		return false
+	}
+	update, _ := getter.Get().(bool)
+	return update
+}
+
+// Case is a single test case in a YAML file.
+type Case struct {
+	// Desc describes the test case.
+	// It's used as the name of the subtest.
+	Desc string `yaml:"desc"`
+
+	// Give is the Markdown input.
+	Give string `yaml:"give"`
+
+	// Want is the expected HTML output.
+	// Trailing newlines are ignored in comparisons.
+	Want string `yaml:"want"`
+
+	// Options configures the Extender for this case.
+	Options `yaml:",inline"`
+}
+
+// Options configures the [anchor.Extender] for a test case.
+// Unset options do not change the Extender.
+type Options struct {
+	// Pos is "before" or "after".
+	Pos string `yaml:"pos"`
+
+	// Text is a constant anchor text.
+	Text string `yaml:"text"`
+
+	// MarkdownText is anchor text in inline Markdown.
+	// It takes precedence over Text.
+	MarkdownText string `yaml:"markdownText"`
+
+	// Attrs is a constant set of anchor attributes.
+	Attrs map[string]string `yaml:"attrs"`
+
+	// Links is "keep", "skip", or "unwrap".
+	Links string `yaml:"links"`
+
+	IDPrefix             string `yaml:"idPrefix"`
+	RewriteFragmentLinks bool   `yaml:"rewriteFragmentLinks"`
+	LevelOffset          int    `yaml:"levelOffset"`
+	Levels               []int  `yaml:"levels"`
+
+	// Meta is metadata for the document, as if read from front matter.
+	Meta map[string]any `yaml:"meta"`
+
+	// Placement is "inside", "outside", or "wrapped".
+	Placement    string `yaml:"placement"`
+	WrapperClass string `yaml:"wrapperClass"`
+
+	Spacing *struct {
+		Separator   string `yaml:"separator"`
+		NoSeparator bool   `yaml:"noSeparator"`
+	} `yaml:"spacing"`
+
+	// NoWrap keeps anchors on the same line as the word next to them.
+	NoWrap bool `yaml:"noWrap"`
+
+	Accessibility *struct {
+		LabelPrefix     string `yaml:"labelPrefix"`
+		HiddenText      bool   `yaml:"hiddenText"`
+		HiddenTextClass string `yaml:"hiddenTextClass"`
+	} `yaml:"accessibility"`
+}
+
+// Apply applies these options to the given Extender.
+// It returns an error if an option has an unknown value.
+func (o *Options) Apply(ext *anchor.Extender) error {
+	if len(o.Text) > 0 {
+		ext.Texter = anchor.Text(o.Text)
+	}
+	if len(o.MarkdownText) > 0 {
+		ext.Texter = anchor.MarkdownText(o.MarkdownText)
+	}
+
+	switch strings.ToLower(o.Pos) {
+	case "":
+		// No customization
+	case "before":
+		ext.Position = anchor.Before
+	case "after":
+		ext.Position = anchor.After
+	default:
+		return fmt.Errorf("unknown position %q", o.Pos)
+	}
+
+	if len(o.Attrs) > 0 {
+		ext.Attributer = anchor.Attributes(o.Attrs)
+	}
+
+	switch strings.ToLower(o.Links) {
+	case "":
+		// No customization
+	case "keep":
+		ext.LinkPolicy = anchor.LinkKeep
+	case "skip":
+		ext.LinkPolicy = anchor.LinkSkip
+	case "unwrap":
+		ext.LinkPolicy = anchor.LinkUnwrap
+	default:
+		return fmt.Errorf("unknown link policy %q", o.Links)
+	}
+
+	switch strings.ToLower(o.Placement) {
+	case "":
+		// No customization
+	case "inside":
+		ext.Placement = anchor.Inside
+	case "outside":
+		ext.Placement = anchor.Outside
+	case "wrapped":
+		ext.Placement = anchor.Wrapped
+	default:
+		return fmt.Errorf("unknown placement %q", o.Placement)
+	}
+
+	if len(o.WrapperClass) > 0 {
+		ext.WrapperClass = o.WrapperClass
+	}
+	if len(o.IDPrefix) > 0 {
+		ext.IDPrefix = o.IDPrefix
+	}
+	if o.RewriteFragmentLinks {
+		ext.RewriteFragmentLinks = true
+	}
+	if o.LevelOffset != 0 {
+		ext.LevelOffset = o.LevelOffset
+	}
+	if o.Levels != nil {
+		ext.Levels = o.Levels
+	}
+	if meta := o.Meta; meta != nil {
+		ext.Metadata = func(parser.Context) map[string]any {
+			return meta
+		}
+	}
+
+	if s := o.Spacing; s != nil {
+		ext.Spacing = &anchor.Spacing{
+			Separator:   s.Separator,
+			NoSeparator: s.NoSeparator,
+		}
+	}
+	if o.NoWrap {
+		ext.NoWrap = true
+	}
+
+	if a := o.Accessibility; a != nil {
+		ext.Accessibility = &anchor.Accessibility{
+			LabelPrefix:     a.LabelPrefix,
+			HiddenText:      a.HiddenText,
+			HiddenTextClass: a.HiddenTextClass,
+		}
+	}
+
+	return nil
+}
+
+// Suite is a set of test cases read from a YAML file.
+type Suite struct {
+	// Path is the path to the YAML file.
+	Path string
+
+	// Extender builds the Extender for a test case.
+	// The options of the case are applied to it before use.
+	//
+	// Defaults to an empty Extender.
+	Extender func() *anchor.Extender
+
+	// Options are additional options for goldmark,
+	// for example, other extensions to use alongside the Extender.
+	//
+	// Headers always get automatic IDs and support attributes.
+	Options []goldmark.Option
+
+	// Update rewrites the "want" fields of the YAML file
+	// with the actual output instead of comparing against them.
+	//
+	// Defaults to the value of the -anchortest.update flag,
+	// or the -update flag if the test package defines one.
+	Update bool
+}
+
+// Run runs all test cases in the suite as subtests of t.
+func (s *Suite) Run(t *testing.T) {
+	t.Helper()
+
+	src, err := os.ReadFile(s.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		t.Fatalf("%v: %v", s.Path, err)
+	}
+
+	var cases []Case
+	if err := doc.Decode(&cases); err != nil {
+		t.Fatalf("%v: %v", s.Path, err)
+	}
+
+	update := s.Update || updateFlag()
+	got := make([]string, len(cases))
+	for i, tt := range cases {
+		i, tt := i, tt
+		t.Run(tt.Desc, func(t *testing.T) {
+			if !update {
+				t.Parallel()
+			}
+
+			out, err := s.convert(&tt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got[i] = out
+
+			if update {
+				return
+			}
+			want := strings.TrimSuffix(tt.Want, "\n")
+			if out := strings.TrimSuffix(out, "\n"); out != want {
+				t.Errorf("output mismatch (run with -anchortest.update to accept)\n"+
+					"want:\n%s\n"+
+					"got:\n%s", want, out)
+			}
+		})
+	}
+
+	if update && !t.Failed() {
+		if err := s.write(src, &doc, cases, got); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func (s *Suite) convert(tt *Case) (string, error) {
+	ext := new(anchor.Extender)
+	if s.Extender != nil {
+		ext = s.Extender()
+	}
+	if err := tt.Options.Apply(ext); err != nil {
+		return "", err
+	}
+
+	opts := make([]goldmark.Option, 0, len(s.Options)+2)
+	opts = append(opts, s.Options...)
+	opts = append(opts,
+		goldmark.WithExtensions(ext),
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithAttribute(),
+		),
+	)
+
+	var buf bytes.Buffer
+	if err := goldmark.New(opts...).Convert([]byte(tt.Give), &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// write rewrites the "want" fields of cases in the YAML file
+// whose output changed.
+// The rest of the file is left as-is.
+func (s *Suite) write(src []byte, doc *yaml.Node, cases []Case, got []string) error {
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	items := doc.Content[0].Content
+
+	lines := strings.SplitAfter(string(src), "\n")
+	// Edit from the bottom up so that line numbers stay valid.
+	for i := len(items) - 1; i >= 0; i-- {
+		if i >= len(cases) || !changed(cases[i].Want, got[i]) {
+			continue
+		}
+
+		item := items[i]
+		next := len(lines)
+		if i+1 < len(items) {
+			next = items[i+1].Line - 1
+		}
+		lines = setWant(lines, item, next, got[i])
+	}
+
+	info, err := os.Stat(s.Path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path, []byte(strings.Join(lines, "")), info.Mode())
+}
+
+func changed(want, got string) bool {
+	return strings.TrimSuffix(want, "\n") != strings.TrimSuffix(got, "\n")
+}
+
+// setWant replaces the "want" field of the given case in lines,
+// adding the field if necessary.
+// next is the index of the line that starts the next case.
+func setWant(lines []string, item *yaml.Node, next int, want string) []string {
+	var key *yaml.Node
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		if item.Content[i].Value == "want" {
+			key = item.Content[i]
+		}
+	}
+
+	indent := strings.Repeat(" ", item.Column-1)
+	field := formatWant(indent, want)
+
+	if key == nil {
+		// Add the field after the last line of the case.
+		end := trimBlockEnd(lines, next, len(indent))
+		return slices.Insert(lines, end, field...)
+	}
+
+	start := key.Line - 1
+	end := next
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		if k := item.Content[i]; k.Line > key.Line {
+			end = min(end, k.Line-1)
+		}
+	}
+	end = max(trimBlockEnd(lines, end, len(indent)), start+1)
+	return slices.Replace(lines, start, end, field...)
+}
+
+// trimBlockEnd moves end up past blank lines,
+// and lines that are not indented past the given width,
+// such as comments for the next case.
+func trimBlockEnd(lines []string, end, indent int) int {
+	for end > 0 {
+		line := strings.TrimRight(lines[end-1], "\r\n")
+		trimmed := strings.TrimLeft(line, " ")
+		if len(trimmed) > 0 && len(line)-len(trimmed) > indent {
+			break
+		}
+		if len(trimmed) > 0 && !strings.HasPrefix(trimmed, "#") {
+			// A key of the case itself.
+			break
+		}
+		end--
+	}
+	return end
+}
+
+// formatWant formats the "want" field with the given indentation.
+func formatWant(indent, want string) []string {
+	if isLiteralSafe(want) {
+		lines := []string{indent + "want: |\n"}
+		for _, line := range strings.SplitAfter(strings.TrimSuffix(want, "\n"), "\n") {
+			line = strings.TrimSuffix(line, "\n")
+			if len(line) == 0 {
+				lines = append(lines, "\n")
+			} else {
+				lines = append(lines, indent+"  "+line+"\n")
+			}
+		}
+		return lines
+	}
+
+	value, err := yaml.Marshal(&yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Style: yaml.DoubleQuotedStyle,
+		Value: want,
+	})
+	if err != nil {
+		// Marshaling a string scalar never fails.
+		panic(err)
+	}
+	return []string{indent + "want: " + string(value)}
+}
+
+// isLiteralSafe reports whether s can be written as a YAML literal block
+// that ends with a single newline.
+func isLiteralSafe(s string) bool {
+	return strings.HasSuffix(s, "\n") &&
+		!strings.HasSuffix(s, "\n\n") &&
+		!strings.HasPrefix(s, " ") &&
+		!strings.HasPrefix(s, "\n") &&
+		!strings.ContainsAny(s, "\r\t")
+}
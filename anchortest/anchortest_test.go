@@ -0,0 +1,165 @@
+package anchortest_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/goldmark/anchor"
+	"go.abhg.dev/goldmark/anchor/anchortest"
+)
+
+// Test packages may define their own -update flag
+// alongside the one used by anchortest.
+var _ = flag.Bool("update", false, "update golden files")
+
+type levelTexter struct{}
+
+func (levelTexter) AnchorText(h *anchor.HeaderInfo) []byte {
+	return []byte("h" + string(rune('0'+h.Level)))
+}
+
+func TestSuite_Run(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, `
+- desc: custom texter
+  give: |
+    # Foo
+
+    ## Bar
+  want: |
+    <h1 id="foo">Foo <a class="anchor" href="#foo">h1</a></h1>
+    <h2 id="bar">Bar <a class="anchor" href="#bar">h2</a></h2>
+
+- desc: options
+  pos: before
+  attrs: {class: permalink}
+  give: |
+    # Foo
+  want: |
+    <h1 id="foo"><a class="permalink" href="#foo">h1</a> Foo</h1>
+`)
+
+	suite := anchortest.Suite{
+		Path: path,
+		Extender: func() *anchor.Extender {
+			return &anchor.Extender{Texter: levelTexter{}}
+		},
+	}
+	suite.Run(t)
+}
+
+func TestSuite_update(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, `# Header comment.
+- desc: unchanged
+  give: |
+    # Foo
+  want: "<h1 id=\"foo\">Foo <a class=\"anchor\" href=\"#foo\">¶</a></h1>"
+
+# Comment for the next case.
+- desc: changed
+  want: |
+    <h1>wrong</h1>
+
+    <p>wrong</p>
+  give: |
+    # Foo
+
+- desc: missing
+  pos: before
+  give: |
+    # Bar
+
+- desc: single line
+  give: "# Baz"
+  want: x
+`)
+
+	t.Run("update", func(t *testing.T) {
+		suite := anchortest.Suite{Path: path, Update: true}
+		suite.Run(t)
+	})
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `# Header comment.
+- desc: unchanged
+  give: |
+    # Foo
+  want: "<h1 id=\"foo\">Foo <a class=\"anchor\" href=\"#foo\">¶</a></h1>"
+
+# Comment for the next case.
+- desc: changed
+  want: |
+    <h1 id="foo">Foo <a class="anchor" href="#foo">¶</a></h1>
+  give: |
+    # Foo
+
+- desc: missing
+  pos: before
+  give: |
+    # Bar
+  want: |
+    <h1 id="bar"><a class="anchor" href="#bar">¶</a> Bar</h1>
+
+- desc: single line
+  give: "# Baz"
+  want: |
+    <h1 id="baz">Baz <a class="anchor" href="#baz">¶</a></h1>
+`, string(got))
+
+	t.Run("check", func(t *testing.T) {
+		suite := anchortest.Suite{Path: path}
+		suite.Run(t)
+	})
+}
+
+func TestOptions_Apply_errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give anchortest.Options
+		want string
+	}{
+		{
+			desc: "position",
+			give: anchortest.Options{Pos: "middle"},
+			want: `unknown position "middle"`,
+		},
+		{
+			desc: "links",
+			give: anchortest.Options{Links: "drop"},
+			want: `unknown link policy "drop"`,
+		},
+		{
+			desc: "placement",
+			give: anchortest.Options{Placement: "above"},
+			want: `unknown placement "above"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.give.Apply(new(anchor.Extender))
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func writeFile(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "tests.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+	return path
+}
@@ -1,9 +1,13 @@
 package anchor
 
 import (
+	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
 )
 
 // Kind is the NodeKind used by anchor nodes.
@@ -23,16 +27,161 @@ type Node struct {
 	// Typically this is a fixed string
 	// like '¶' or '#'.
 	Value []byte
+
+	// Position specifies where in the header text
+	// the anchor should be rendered.
+	//
+	// The Transformer sets this to its own Position,
+	// and renderers place the anchor accordingly.
+	Position Position
+
+	// Section holds statistics about the section
+	// introduced by the header.
+	//
+	// This is nil unless statistics are enabled with [Stats].
+	Section *SectionStats
+
+	// render holds Renderer settings overridden for this conversion
+	// with WithContextOptions, or nil if there are none.
+	render *renderOptions
 }
 
 // Kind reports that this is a Anchor node.
 func (*Node) Kind() ast.NodeKind { return Kind }
 
 // Dump dumps this node to stdout for debugging.
+//
+// In addition to the fields of the node,
+// this includes the attributes and href of the anchor,
+// and the source segment of the header it's for.
 func (n *Node) Dump(src []byte, level int) {
-	ast.DumpHelper(n, src, level, map[string]string{
-		"ID":    string(n.ID),
-		"Value": string(n.Value),
-		"Level": strconv.Itoa(n.Level),
-	}, nil)
+	kv := map[string]string{
+		"ID":       string(n.ID),
+		"Value":    string(n.Value),
+		"Level":    strconv.Itoa(n.Level),
+		"Position": n.Position.String(),
+		"Href":     string(n.href()),
+	}
+	if attrs := n.Attributes(); len(attrs) > 0 {
+		var sb strings.Builder
+		for i, attr := range attrs {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.Write(attr.Name)
+			sb.WriteByte('=')
+			sb.WriteString(strconv.Quote(attributeString(attr.Value)))
+		}
+		kv["Attributes"] = sb.String()
+	}
+	if seg, ok := n.headingSegment(); ok {
+		kv["Heading"] = fmt.Sprintf("%d:%d %q", seg.Start, seg.Stop, seg.Value(src))
+	}
+	ast.DumpHelper(n, src, level, kv, nil)
+}
+
+// MarshalJSON encodes this node as JSON.
+// Use it to take snapshots of anchors for tests.
+//
+//	{
+//	  "kind": "Anchor",
+//	  "id": "foo",
+//	  "href": "#foo",
+//	  "level": 1,
+//	  "value": "¶",
+//	  "position": "After",
+//	  "attributes": {"class": "anchor"},
+//	  "heading": {"start": 2, "stop": 5}
+//	}
+//
+// The "attributes", "heading", and "section" fields
+// are omitted if they are not set.
+// The child nodes of the anchor are not included.
+func (n *Node) MarshalJSON() ([]byte, error) {
+	v := nodeJSON{
+		Kind:     n.Kind().String(),
+		ID:       string(n.ID),
+		Href:     string(n.href()),
+		Level:    n.Level,
+		Value:    string(n.Value),
+		Position: n.Position.String(),
+	}
+	if attrs := n.Attributes(); len(attrs) > 0 {
+		v.Attributes = make(map[string]string, len(attrs))
+		for _, attr := range attrs {
+			v.Attributes[string(attr.Name)] = attributeString(attr.Value)
+		}
+	}
+	if seg, ok := n.headingSegment(); ok {
+		v.Heading = &segmentJSON{Start: seg.Start, Stop: seg.Stop}
+	}
+	if s := n.Section; s != nil {
+		v.Section = &sectionJSON{
+			Words:       s.Words,
+			CodeBlocks:  s.CodeBlocks,
+			Links:       s.Links,
+			ReadingTime: s.ReadingTime.String(),
+		}
+	}
+	return json.Marshal(v)
+}
+
+type nodeJSON struct {
+	Kind       string            `json:"kind"`
+	ID         string            `json:"id"`
+	Href       string            `json:"href"`
+	Level      int               `json:"level"`
+	Value      string            `json:"value"`
+	Position   string            `json:"position"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Heading    *segmentJSON      `json:"heading,omitempty"`
+	Section    *sectionJSON      `json:"section,omitempty"`
+}
+
+type segmentJSON struct {
+	Start int `json:"start"`
+	Stop  int `json:"stop"`
+}
+
+type sectionJSON struct {
+	Words       int    `json:"words"`
+	CodeBlocks  int    `json:"codeBlocks"`
+	Links       int    `json:"links"`
+	ReadingTime string `json:"readingTime"`
+}
+
+// href returns the value of the href attribute of this anchor,
+// without HTML escaping.
+func (n *Node) href() []byte {
+	fragment := escapeFragment(n.ID)
+	href := make([]byte, 0, len(fragment)+1)
+	href = append(href, '#')
+	return append(href, fragment...)
+}
+
+// headingSegment reports the segment of the source
+// that holds the text of the header this anchor is for.
+func (n *Node) headingSegment() (text.Segment, bool) {
+	h, ok := anchorHeading(n).(*ast.Heading)
+	if !ok {
+		return text.Segment{}, false
+	}
+
+	lines := h.Lines()
+	if lines.Len() == 0 {
+		return text.Segment{}, false
+	}
+	return text.NewSegment(lines.At(0).Start, lines.At(lines.Len()-1).Stop), true
+}
+
+// attributeString returns the string form of an attribute value.
+func attributeString(v any) string {
+	switch v := v.(type) {
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
 }
@@ -2,12 +2,16 @@ package anchor
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
 )
 
 func TestNode_Kind(t *testing.T) {
@@ -32,6 +36,92 @@ func TestNode_Dump(t *testing.T) {
 	assert.Contains(t, got, "}\n")
 }
 
+func TestNode_DumpWithHeading(t *testing.T) {
+	src := []byte("# Foo bar\n")
+	doc := goldmark.New(
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithExtensions(&Extender{
+			Position:   Before,
+			Attributer: Attributes{"class": "anchor", "title": `say "hi"`},
+		}),
+	).Parser().Parse(text.NewReader(src))
+
+	getStdout := hijackStdout(t)
+	doc.Dump(src, 0)
+	got := getStdout()
+
+	assert.Contains(t, got, "        ID: foo-bar\n")
+	assert.Contains(t, got, "        Position: Before\n")
+	assert.Contains(t, got, "        Href: #foo-bar\n")
+	assert.Contains(t, got, `        Heading: 2:9 "Foo bar"`+"\n")
+	assert.Contains(t, got, `        Attributes: class="anchor" title="say \"hi\""`+"\n")
+}
+
+func TestNode_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("minimal", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := json.Marshal(&Node{
+			ID:    []byte("a b"),
+			Level: 2,
+			Value: []byte("#"),
+		})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"kind": "Anchor",
+			"id": "a b",
+			"href": "#a%20b",
+			"level": 2,
+			"value": "#",
+			"position": "After"
+		}`, string(got))
+	})
+
+	t.Run("document", func(t *testing.T) {
+		t.Parallel()
+
+		md := goldmark.New(
+			goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+			goldmark.WithExtensions(&Extender{
+				Stats: &Stats{},
+			}),
+		)
+
+		ctx := parser.NewContext()
+		src := []byte("# Foo\n\nHello world.\n\n## Bar\n")
+		md.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+		got, err := json.Marshal(Nodes(ctx))
+		require.NoError(t, err)
+		assert.JSONEq(t, `[
+			{
+				"kind": "Anchor",
+				"id": "foo",
+				"href": "#foo",
+				"level": 1,
+				"value": "¶",
+				"position": "After",
+				"attributes": {"class": "anchor"},
+				"heading": {"start": 2, "stop": 5},
+				"section": {"words": 4, "codeBlocks": 0, "links": 0, "readingTime": "1.2s"}
+			},
+			{
+				"kind": "Anchor",
+				"id": "bar",
+				"href": "#bar",
+				"level": 2,
+				"value": "¶",
+				"position": "After",
+				"attributes": {"class": "anchor"},
+				"heading": {"start": 24, "stop": 27},
+				"section": {"words": 1, "codeBlocks": 0, "links": 0, "readingTime": "300ms"}
+			}
+		]`, string(got))
+	})
+}
+
 func hijackStdout(t testing.TB) func() string {
 	stdout := os.Stdout
 	t.Cleanup(func() {
@@ -0,0 +1,223 @@
+package anchor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	// ErrInvalidAttribute indicates that an attribute name
+	// contains characters that are not allowed in attribute names.
+	ErrInvalidAttribute = errors.New("invalid attribute name")
+
+	// ErrReservedAttribute indicates that an attribute
+	// is generated by the Renderer and cannot be set on anchors,
+	// for example, 'href'.
+	ErrReservedAttribute = errors.New("reserved attribute")
+
+	// ErrUnsafeAttribute indicates that an attribute can run scripts,
+	// for example, event handlers like 'onclick',
+	// or URLs with the 'javascript:' scheme.
+	ErrUnsafeAttribute = errors.New("unsafe attribute")
+
+	// ErrAttributeNotAllowed indicates that an attribute
+	// was rejected by an [AttributePolicy].
+	ErrAttributeNotAllowed = errors.New("attribute not allowed")
+)
+
+// AttributeError is returned when an anchor has an attribute
+// that fails validation.
+//
+// Use errors.Is to check the reason for the failure.
+//
+//	if errors.Is(err, anchor.ErrUnsafeAttribute) {
+//		// ...
+//	}
+type AttributeError struct {
+	// Name of the attribute.
+	Name string
+
+	// Err is the reason the attribute was rejected.
+	// This is one of ErrInvalidAttribute, ErrReservedAttribute,
+	// ErrUnsafeAttribute, or ErrAttributeNotAllowed.
+	Err error
+}
+
+func (e *AttributeError) Error() string {
+	return fmt.Sprintf("attribute %q: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the reason the attribute was rejected.
+func (e *AttributeError) Unwrap() error {
+	return e.Err
+}
+
+// AttributePolicy specifies which attributes anchors may have.
+//
+// Regardless of the policy, the following are always rejected:
+//
+//   - attribute names with characters other than
+//     ASCII letters, digits, '-', '_', '.', and ':'
+//   - 'href', which is generated by the [Renderer]
+//   - event handlers like 'onclick'
+//   - URL attributes like 'src' using the 'javascript:', 'vbscript:',
+//     or 'data:' schemes
+//
+// A nil AttributePolicy applies only these rules.
+type AttributePolicy struct {
+	// Allow lists the names of attributes that anchors may have.
+	// Names are matched case-insensitively.
+	//
+	// Attributes added by the Transformer, like 'data-reading-time',
+	// must also be listed.
+	//
+	// If empty, all attributes are allowed
+	// except those listed in Deny.
+	Allow []string
+
+	// Deny lists the names of attributes that anchors may not have.
+	// Names are matched case-insensitively.
+	Deny []string
+}
+
+// Validate reports an [*AttributeError] if an anchor
+// may not have an attribute with the given name and value.
+func (p *AttributePolicy) Validate(name, value string) error {
+	return p.validate([]byte(name), []byte(value))
+}
+
+func (p *AttributePolicy) validate(name, value []byte) error {
+	var err error
+	switch {
+	case !isAttributeName(name):
+		err = ErrInvalidAttribute
+	case equalFold("href", name):
+		err = ErrReservedAttribute
+	case len(name) > 2 && equalFold("on", name[:2]):
+		err = ErrUnsafeAttribute
+	case isURLAttribute(name) && hasScriptScheme(value):
+		err = ErrUnsafeAttribute
+	case p != nil && !p.allows(name):
+		err = ErrAttributeNotAllowed
+	default:
+		return nil
+	}
+	return &AttributeError{Name: string(name), Err: err}
+}
+
+// validateAttributes validates all attributes of the given anchor.
+func (p *AttributePolicy) validateAttributes(n *Node) error {
+	for _, attr := range n.Attributes() {
+		var value []byte
+		switch v := attr.Value.(type) {
+		case []byte:
+			value = v
+		case string:
+			value = util.StringToReadOnlyBytes(v)
+		}
+
+		if err := p.validate(attr.Name, value); err != nil {
+			return fmt.Errorf("anchor %q: %w", n.ID, err)
+		}
+	}
+	return nil
+}
+
+func (p *AttributePolicy) allows(name []byte) bool {
+	if containsFold(p.Deny, name) {
+		return false
+	}
+	return len(p.Allow) == 0 || containsFold(p.Allow, name)
+}
+
+// isAttributeName reports whether name is a non-empty attribute name
+// made up only of ASCII letters, digits, '-', '_', '.', and ':'.
+func isAttributeName(name []byte) bool {
+	if len(name) == 0 {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// _urlAttributes are attributes whose values browsers interpret as URLs.
+var _urlAttributes = []string{
+	"action", "background", "cite", "formaction", "ping",
+	"poster", "src", "srcset", "xlink:href",
+}
+
+func isURLAttribute(name []byte) bool {
+	return containsFold(_urlAttributes, name)
+}
+
+// containsFold reports whether any of the given strings
+// is equal to b under ASCII case-folding.
+func containsFold(ss []string, b []byte) bool {
+	for _, s := range ss {
+		if equalFold(s, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalFold reports whether s and b are equal under ASCII case-folding.
+// It avoids converting b to a string for strings.EqualFold.
+func equalFold(s string, b []byte) bool {
+	if len(s) != len(b) {
+		return false
+	}
+	for i := range len(s) {
+		if toLowerASCII(s[i]) != toLowerASCII(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func toLowerASCII(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// _scriptSchemes are URL schemes that browsers may execute as scripts.
+var _scriptSchemes = []string{"javascript", "vbscript", "data"}
+
+// hasScriptScheme reports whether the given URL uses a scheme
+// that browsers may execute as a script.
+//
+// Like browsers, it ignores leading whitespace and control characters,
+// and tabs and newlines inside the scheme.
+func hasScriptScheme(url []byte) bool {
+	url = bytes.TrimLeftFunc(url, func(r rune) bool {
+		return r <= ' '
+	})
+
+	scheme := make([]byte, 0, len("javascript"))
+	for _, c := range url {
+		switch c {
+		case '\t', '\n', '\r':
+			continue
+		case ':':
+			return containsFold(_scriptSchemes, scheme)
+		}
+
+		scheme = append(scheme, c)
+		if len(scheme) > len("javascript") {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package anchor
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+)
+
+func TestAttributePolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc   string
+		policy *AttributePolicy
+		name   string
+		value  string
+		want   error // nil if valid
+	}{
+		{desc: "class", name: "class", value: "anchor"},
+		{desc: "data", name: "data-foo", value: "javascript:alert(1)"},
+		{desc: "namespaced", name: "xml:lang", value: "en"},
+		{desc: "on alone", name: "on", value: "x"},
+		{desc: "safe src", name: "src", value: "https://example.com/a.png"},
+		{desc: "empty name", name: "", want: ErrInvalidAttribute},
+		{desc: "space in name", name: "a b", want: ErrInvalidAttribute},
+		{desc: "markup in name", name: `"><script>`, want: ErrInvalidAttribute},
+		{desc: "href", name: "href", value: "#foo", want: ErrReservedAttribute},
+		{desc: "href uppercase", name: "HREF", value: "#foo", want: ErrReservedAttribute},
+		{desc: "onclick", name: "onclick", value: "alert(1)", want: ErrUnsafeAttribute},
+		{desc: "onclick mixed case", name: "OnClick", value: "alert(1)", want: ErrUnsafeAttribute},
+		{desc: "javascript src", name: "src", value: "javascript:alert(1)", want: ErrUnsafeAttribute},
+		{desc: "obfuscated scheme", name: "xlink:href", value: " \x01Java\tScript:alert(1)", want: ErrUnsafeAttribute},
+		{desc: "vbscript", name: "formaction", value: "vbscript:x", want: ErrUnsafeAttribute},
+		{desc: "data url", name: "poster", value: "data:text/html,x", want: ErrUnsafeAttribute},
+		{
+			desc:   "allowed",
+			policy: &AttributePolicy{Allow: []string{"class", "title"}},
+			name:   "Title",
+			value:  "x",
+		},
+		{
+			desc:   "not in allow list",
+			policy: &AttributePolicy{Allow: []string{"class"}},
+			name:   "title",
+			value:  "x",
+			want:   ErrAttributeNotAllowed,
+		},
+		{
+			desc:   "denied",
+			policy: &AttributePolicy{Deny: []string{"style"}},
+			name:   "STYLE",
+			value:  "color: red",
+			want:   ErrAttributeNotAllowed,
+		},
+		{
+			desc:   "deny wins",
+			policy: &AttributePolicy{Allow: []string{"style"}, Deny: []string{"style"}},
+			name:   "style",
+			value:  "color: red",
+			want:   ErrAttributeNotAllowed,
+		},
+		{
+			desc:   "allow cannot permit unsafe",
+			policy: &AttributePolicy{Allow: []string{"onclick"}},
+			name:   "onclick",
+			value:  "alert(1)",
+			want:   ErrUnsafeAttribute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.policy.Validate(tt.name, tt.value)
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, tt.want)
+			var attrErr *AttributeError
+			require.True(t, errors.As(err, &attrErr))
+			assert.Equal(t, tt.name, attrErr.Name)
+		})
+	}
+}
+
+func TestRenderer_attributePolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc      string
+		placement Placement
+		attrs     Attributes
+		policy    *AttributePolicy
+		want      error
+	}{
+		{
+			desc:  "href",
+			attrs: Attributes{"href": "javascript:alert(1)"},
+			want:  ErrReservedAttribute,
+		},
+		{
+			desc:      "onclick outside",
+			placement: Outside,
+			attrs:     Attributes{"class": "anchor", "onclick": "alert(1)"},
+			want:      ErrUnsafeAttribute,
+		},
+		{
+			desc:   "policy",
+			attrs:  Attributes{"class": "anchor", "title": "x"},
+			policy: &AttributePolicy{Allow: []string{"class"}},
+			want:   ErrAttributeNotAllowed,
+		},
+		{
+			desc:   "allowed",
+			attrs:  Attributes{"class": "anchor", "title": "x"},
+			policy: &AttributePolicy{Allow: []string{"class", "title"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			md := goldmark.New(
+				goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+				goldmark.WithExtensions(&Extender{
+					Attributer:      tt.attrs,
+					Placement:       tt.placement,
+					AttributePolicy: tt.policy,
+				}),
+			)
+
+			var buf bytes.Buffer
+			err := md.Convert([]byte("# Foo\n"), &buf)
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, tt.want)
+			assert.Contains(t, err.Error(), `anchor "foo"`)
+			assert.NotContains(t, buf.String(), "alert(1)")
+		})
+	}
+}
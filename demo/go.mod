@@ -10,3 +10,8 @@ require (
 	github.com/yuin/goldmark v1.7.16
 	go.abhg.dev/goldmark/anchor v0.2.0
 )
+
+require (
+	golang.org/x/net v0.49.0 // indirect
+	golang.org/x/text v0.33.0 // indirect
+)
@@ -0,0 +1,173 @@
+package anchor
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	_zeroWidthJoiner   = '\u200d'
+	_variationSelector = '\ufe0f' // emoji presentation
+	_combiningKeycap   = '\u20e3'
+)
+
+// matchEmoji reports the size in bytes of the emoji at the start of b.
+// It returns 0 if b does not start with an emoji.
+//
+// It recognizes single emoji, emoji with modifiers (e.g. skin tones),
+// ZWJ sequences, flags, and keycaps.
+// Characters that are displayed as text by default (e.g. "©")
+// are emoji only if they're followed by a variation selector
+// that requests emoji presentation.
+func matchEmoji(b []byte) (size int) {
+	r, n := utf8.DecodeRune(b)
+
+	switch {
+	case isRegionalIndicator(r):
+		r2, n2 := utf8.DecodeRune(b[n:])
+		if !isRegionalIndicator(r2) {
+			return 0
+		}
+		return n + n2
+
+	case r == '#' || r == '*' || ('0' <= r && r <= '9'):
+		// Keycaps are a character followed by
+		// an optional variation selector and a combining keycap.
+		size := n
+		if r, n := utf8.DecodeRune(b[size:]); r == _variationSelector {
+			size += n
+		}
+		if r, n := utf8.DecodeRune(b[size:]); r == _combiningKeycap {
+			return size + n
+		}
+		return 0
+
+	case !isEmoji(b):
+		return 0
+	}
+
+	size = n
+	for size < len(b) {
+		r, n := utf8.DecodeRune(b[size:])
+		if r == _variationSelector || isSkinTone(r) || isTag(r) {
+			size += n
+			continue
+		}
+
+		if r != _zeroWidthJoiner || !isEmoji(b[size+n:]) {
+			break
+		}
+		_, nextSize := utf8.DecodeRune(b[size+n:])
+		size += n + nextSize
+	}
+
+	return size
+}
+
+// isEmoji reports whether b starts with a character
+// that is displayed as an emoji.
+//
+// That's the case for characters with the Emoji_Presentation property,
+// and for other non-ASCII characters followed by a variation selector
+// that requests emoji presentation.
+func isEmoji(b []byte) bool {
+	r, n := utf8.DecodeRune(b)
+	if unicode.Is(_emojiPresentation, r) {
+		return true
+	}
+	if r < utf8.RuneSelf {
+		return false
+	}
+	next, _ := utf8.DecodeRune(b[n:])
+	return next == _variationSelector
+}
+
+func isRegionalIndicator(r rune) bool { return 0x1f1e6 <= r && r <= 0x1f1ff }
+
+func isSkinTone(r rune) bool { return 0x1f3fb <= r && r <= 0x1f3ff }
+
+// isTag reports whether r is a tag character,
+// used in subdivision flags like England's.
+func isTag(r rune) bool { return 0xe0020 <= r && r <= 0xe007f }
+
+// _emojiPresentation holds the characters
+// with the Emoji_Presentation property as of Unicode 15.1,
+// except for regional indicators and skin tone modifiers,
+// which are handled separately.
+//
+// These are displayed as emoji by default.
+var _emojiPresentation = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x231a, 0x231b, 1},
+		{0x23e9, 0x23ec, 1},
+		{0x23f0, 0x23f3, 3},
+		{0x25fd, 0x25fe, 1},
+		{0x2614, 0x2615, 1},
+		{0x2648, 0x2653, 1},
+		{0x267f, 0x2693, 20},
+		{0x26a1, 0x26aa, 9},
+		{0x26ab, 0x26bd, 18},
+		{0x26be, 0x26c4, 6},
+		{0x26c5, 0x26ce, 9},
+		{0x26d4, 0x26ea, 22},
+		{0x26f2, 0x26f3, 1},
+		{0x26f5, 0x26fa, 5},
+		{0x26fd, 0x2705, 8},
+		{0x270a, 0x270b, 1},
+		{0x2728, 0x274c, 36},
+		{0x274e, 0x2753, 5},
+		{0x2754, 0x2755, 1},
+		{0x2757, 0x2795, 62},
+		{0x2796, 0x2797, 1},
+		{0x27b0, 0x27bf, 15},
+		{0x2b1b, 0x2b1c, 1},
+		{0x2b50, 0x2b55, 5},
+	},
+	R32: []unicode.Range32{
+		{0x1f004, 0x1f0cf, 203},
+		{0x1f18e, 0x1f191, 3},
+		{0x1f192, 0x1f19a, 1},
+		{0x1f201, 0x1f21a, 25},
+		{0x1f22f, 0x1f232, 3},
+		{0x1f233, 0x1f236, 1},
+		{0x1f238, 0x1f23a, 1},
+		{0x1f250, 0x1f251, 1},
+		{0x1f300, 0x1f320, 1},
+		{0x1f32d, 0x1f335, 1},
+		{0x1f337, 0x1f37c, 1},
+		{0x1f37e, 0x1f393, 1},
+		{0x1f3a0, 0x1f3ca, 1},
+		{0x1f3cf, 0x1f3d3, 1},
+		{0x1f3e0, 0x1f3f0, 1},
+		{0x1f3f4, 0x1f3f8, 4},
+		{0x1f3f9, 0x1f3fa, 1},
+		{0x1f400, 0x1f43e, 1},
+		{0x1f440, 0x1f442, 2},
+		{0x1f443, 0x1f4fc, 1},
+		{0x1f4ff, 0x1f53d, 1},
+		{0x1f54b, 0x1f54e, 1},
+		{0x1f550, 0x1f567, 1},
+		{0x1f57a, 0x1f595, 27},
+		{0x1f596, 0x1f5a4, 14},
+		{0x1f5fb, 0x1f64f, 1},
+		{0x1f680, 0x1f6c5, 1},
+		{0x1f6cc, 0x1f6d0, 4},
+		{0x1f6d1, 0x1f6d2, 1},
+		{0x1f6d5, 0x1f6d7, 1},
+		{0x1f6dc, 0x1f6df, 1},
+		{0x1f6eb, 0x1f6ec, 1},
+		{0x1f6f4, 0x1f6fc, 1},
+		{0x1f7e0, 0x1f7eb, 1},
+		{0x1f7f0, 0x1f90c, 284},
+		{0x1f90d, 0x1f93a, 1},
+		{0x1f93c, 0x1f945, 1},
+		{0x1f947, 0x1f9ff, 1},
+		{0x1fa70, 0x1fa7c, 1},
+		{0x1fa80, 0x1fa88, 1},
+		{0x1fa90, 0x1fabd, 1},
+		{0x1fabf, 0x1fac5, 1},
+		{0x1face, 0x1fadb, 1},
+		{0x1fae0, 0x1fae8, 1},
+		{0x1faf0, 0x1faf8, 1},
+	},
+}
@@ -0,0 +1,66 @@
+package anchor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchEmoji(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give string
+		want int // size of the emoji
+	}{
+		{desc: "empty", give: ""},
+		{desc: "ascii", give: "foo"},
+		{desc: "letter", give: "é"},
+		{desc: "trademark", give: "™"},
+		{desc: "digit", give: "1 foo"},
+		{desc: "single", give: "🚀 foo", want: len("🚀")},
+		{desc: "dingbat", give: "✅", want: len("✅")},
+		{desc: "hourglass", give: "⌛", want: len("⌛")},
+		{desc: "variation selector", give: "❤️foo", want: len("❤️")},
+		{desc: "text with variation selector", give: "©️", want: len("©️")},
+		{desc: "skin tone", give: "👍🏽", want: len("👍🏽")},
+		{desc: "lone skin tone", give: "🏽"},
+		{desc: "zwj sequence", give: "👨‍💻 foo", want: len("👨‍💻")},
+		{
+			desc: "zwj sequence with variation selector",
+			give: "🏳️‍⚧️",
+			want: len("🏳️‍⚧️"),
+		},
+		{desc: "trailing zwj", give: "👨‍a", want: len("👨")},
+		{desc: "zwj text symbol", give: "👨‍©", want: len("👨")},
+		{desc: "flag", give: "🇺🇸", want: len("🇺🇸")},
+		{desc: "lone regional indicator", give: "🇺x"},
+		{desc: "keycap", give: "1️⃣", want: len("1️⃣")},
+		{desc: "keycap without variation selector", give: "#⃣", want: len("#⃣")},
+		{
+			desc: "tag sequence",
+			give: "🏴\U000e0067\U000e0062\U000e0065\U000e006e\U000e0067\U000e007f",
+			want: len("🏴\U000e0067\U000e0062\U000e0065\U000e006e\U000e0067\U000e007f"),
+		},
+
+		// Symbols that are displayed as text by default.
+		{desc: "check mark", give: "✓"},
+		{desc: "white star", give: "☆"},
+		{desc: "squared letter", give: "🄰"},
+		{desc: "heart suit", give: "♥"},
+		{desc: "arrow", give: "→"},
+		{desc: "copyright", give: "©"},
+		{desc: "white heart suit", give: "♡"},
+		{desc: "text heart", give: "❤"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, matchEmoji([]byte(tt.give)))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Package emojiname names emoji after their Unicode character names.
+//
+// Use it with the EmojiName setting of goldmark-anchor
+// to replace emoji written in header text with names
+// when the EmojiShortcode policy is in use.
+//
+//	&anchor.Extender{
+//		Emoji:     anchor.EmojiShortcode,
+//		EmojiName: emojiname.Name,
+//	}
+//
+// This package embeds the Unicode character names table,
+// which adds about a megabyte to binaries that import it.
+package emojiname
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/runenames"
+)
+
+const (
+	_zeroWidthJoiner = '\u200d'
+	_combiningKeycap = '\u20e3'
+)
+
+var _nameReplacer = strings.NewReplacer(" ", "_", "-", "_")
+
+// Name returns a lowercase name for the given emoji,
+// derived from the Unicode names of its characters.
+// For example:
+//
+//	🚀    rocket
+//	👍🏽    thumbs_up_sign
+//	👨‍💻    man_personal_computer
+//	🇺🇸    flag_us
+//	1️⃣    keycap_1
+//
+// Modifiers like skin tones and variation selectors
+// don't contribute to the name.
+// Name returns an empty string for an empty string.
+func Name(emoji string) string {
+	if emoji == "" {
+		return ""
+	}
+
+	if base, ok := strings.CutSuffix(emoji, string(_combiningKeycap)); ok {
+		r, _ := utf8.DecodeRuneInString(base)
+		return "keycap_" + string(r)
+	}
+
+	if letters, ok := flagLetters(emoji); ok {
+		return "flag_" + letters
+	}
+
+	// Each part of a ZWJ sequence contributes the name
+	// of its first character.
+	parts := strings.Split(emoji, string(_zeroWidthJoiner))
+	names := make([]string, 0, len(parts))
+	for _, part := range parts {
+		r, _ := utf8.DecodeRuneInString(part)
+		if part == "" || r == utf8.RuneError {
+			continue
+		}
+		names = append(names, runeName(r))
+	}
+	return strings.Join(names, "_")
+}
+
+// flagLetters returns the lowercase region code of a flag emoji
+// made of two regional indicators.
+func flagLetters(emoji string) (string, bool) {
+	r1, n1 := utf8.DecodeRuneInString(emoji)
+	r2, n2 := utf8.DecodeRuneInString(emoji[n1:])
+	if n1+n2 != len(emoji) || !isRegionalIndicator(r1) || !isRegionalIndicator(r2) {
+		return "", false
+	}
+	return string([]rune{'a' + r1 - 0x1f1e6, 'a' + r2 - 0x1f1e6}), true
+}
+
+func isRegionalIndicator(r rune) bool { return 0x1f1e6 <= r && r <= 0x1f1ff }
+
+func runeName(r rune) string {
+	return _nameReplacer.Replace(strings.ToLower(runenames.Name(r)))
+}
@@ -0,0 +1,42 @@
+package emojiname
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give string
+		want string
+	}{
+		{desc: "empty", give: "", want: ""},
+		{desc: "single", give: "🚀", want: "rocket"},
+		{desc: "dingbat", give: "✅", want: "white_heavy_check_mark"},
+		{desc: "variation selector", give: "❤️", want: "heavy_black_heart"},
+		{desc: "text with variation selector", give: "©️", want: "copyright_sign"},
+		{desc: "skin tone", give: "👍🏽", want: "thumbs_up_sign"},
+		{desc: "zwj sequence", give: "👨‍💻", want: "man_personal_computer"},
+		{desc: "flag", give: "🇺🇸", want: "flag_us"},
+		{desc: "keycap", give: "1️⃣", want: "keycap_1"},
+		{desc: "keycap without variation selector", give: "#⃣", want: "keycap_#"},
+		{
+			desc: "tag sequence",
+			give: "🏴\U000e0067\U000e0062\U000e0065\U000e006e\U000e0067\U000e007f",
+			want: "waving_black_flag",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, Name(tt.give))
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package anchor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmojiPolicy_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give EmojiPolicy
+		want string
+	}{
+		{desc: "keep", give: EmojiKeep, want: "EmojiKeep"},
+		{desc: "strip", give: EmojiStrip, want: "EmojiStrip"},
+		{desc: "shortcode", give: EmojiShortcode, want: "EmojiShortcode"},
+		{desc: "unknown", give: 42, want: "EmojiPolicy(42)"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.give.String())
+		})
+	}
+}
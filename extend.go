@@ -36,33 +36,204 @@ type Extender struct {
 	// Defaults to adding a 'class="anchor"' attribute.
 	Attributer Attributer
 
+	// LinkPolicy specifies how headers that contain links are handled.
+	//
+	// Defaults to LinkKeep.
+	LinkPolicy LinkPolicy
+
+	// Stats configures per-section statistics.
+	//
+	// Statistics are not computed if this is unset.
+	// Use [Nodes] to retrieve the statistics after a conversion.
+	Stats *Stats
+
+	// IDPrefix is added to the IDs of all headers in the document,
+	// including IDs specified explicitly in the document.
+	//
+	// Use this to avoid collisions between header IDs
+	// when rendering multiple documents into the same page.
+	// See [Transformer.IDPrefix] for how it combines with [IDs].
+	IDPrefix string
+
+	// RewriteFragmentLinks specifies whether links inside the document
+	// that point to its headers (e.g. "[see](#install)")
+	// should be updated to use the prefixed IDs.
+	//
+	// This has no effect if IDPrefix is unset.
+	RewriteFragmentLinks bool
+
+	// LevelOffset shifts the levels of all headers in the document.
+	// For example, with a LevelOffset of 1, '#' headers become <h2>.
+	// Levels are clamped to the range 1 to 6.
+	//
+	// Anchors and the HeaderInfo passed to Texter and Attributer
+	// use the shifted levels.
+	LevelOffset int
+
+	// Levels restricts anchors to headers of the given levels.
+	//
+	// Defaults to generating anchors for headers of all levels.
+	Levels []int
+
+	// Metadata retrieves metadata for the document being converted,
+	// for example, from its front matter.
+	// The following keys override the Extender's settings
+	// for that document:
+	//
+	//	anchors: false         # don't generate anchors
+	//	anchor_text: "#"       # use a constant anchor text
+	//	anchor_levels: [2, 3]  # generate anchors only for these levels
+	//
+	// Use meta.Get from goldmark-meta to read front matter.
+	Metadata MetadataFunc
+
+	// Emoji specifies how emoji are handled
+	// in the header text reported by HeaderInfo.Text.
+	//
+	// Defaults to EmojiKeep.
+	Emoji EmojiPolicy
+
+	// EmojiName names emoji written directly in header text
+	// if Emoji is EmojiShortcode.
+	// See [PlainText.EmojiName] for details.
+	EmojiName func(emoji string) string
+
 	// Unsafe specifies whether the Texter values will be escaped or not.
 	// Setting this to true can lead to HTML injection if you don't handle
 	// Texter values with care.
 	//
 	// Defaults to false.
 	Unsafe bool
+
+	// Sanitizer cleans up HTML in anchor text before it's written.
+	// Use this to allow limited markup in anchor text,
+	// for example, with [AllowlistSanitizer].
+	//
+	// This has no effect if Unsafe is set.
+	// If unset, anchor text is HTML escaped.
+	Sanitizer Sanitizer
+
+	// Placement specifies whether the anchor is rendered
+	// inside or outside the heading element.
+	//
+	// Defaults to Inside.
+	Placement Placement
+
+	// WrapperClass is the class of the <div> that wraps headings
+	// if Placement is Wrapped.
+	//
+	// Defaults to "heading-wrapper".
+	WrapperClass string
+
+	// Spacing configures the whitespace between the heading text
+	// and anchors rendered inside the heading.
+	// It has no effect if Placement is not Inside.
+	//
+	// Defaults to a single space.
+	Spacing *Spacing
+
+	// NoWrap keeps anchors on the same line
+	// as the word of the heading next to them.
+	// See [Transformer.NoWrap] for details.
+	//
+	// This has no effect if Placement is not Inside.
+	NoWrap bool
+
+	// Accessibility configures accessible rendering of anchors.
+	//
+	// If unset, anchors are rendered without additional markup
+	// for assistive technologies.
+	Accessibility *Accessibility
+
+	// AttributePolicy specifies which attributes anchors may have.
+	// Conversion fails with an [*AttributeError]
+	// if an anchor has an attribute that isn't allowed.
+	//
+	// If unset, unsafe attributes like event handlers are rejected,
+	// and all other attributes are allowed.
+	AttributePolicy *AttributePolicy
 }
 
 var _ goldmark.Extender = (*Extender)(nil)
 
 // Extend extends the provided Goldmark Markdown.
+//
+// Only the first Extender installed into a Markdown takes effect.
+// Extending it again, with the same or a different Extender,
+// has no effect.
 func (e *Extender) Extend(md goldmark.Markdown) {
-	md.Parser().AddOptions(
+	var parserExtended, rendererExtended bool
+	md.Parser().AddOptions(markExtended{&parserExtended})
+	md.Renderer().AddOptions(markExtended{&rendererExtended})
+
+	if !parserExtended {
+		e.extendParser(md.Parser())
+	}
+	if !rendererExtended {
+		e.extendRenderer(md.Renderer())
+	}
+}
+
+func (e *Extender) extendParser(p parser.Parser) {
+	p.AddOptions(
 		parser.WithASTTransformers(
 			util.Prioritized(&Transformer{
 				Texter:     e.Texter,
 				Position:   e.Position,
 				Attributer: e.Attributer,
+				LinkPolicy: e.LinkPolicy,
+				Stats:      e.Stats,
+
+				IDPrefix:             e.IDPrefix,
+				RewriteFragmentLinks: e.RewriteFragmentLinks,
+				LevelOffset:          e.LevelOffset,
+				Levels:               e.Levels,
+				Metadata:             e.Metadata,
+				Emoji:                e.Emoji,
+				EmojiName:            e.EmojiName,
+				NoWrap:               e.NoWrap && e.Placement == Inside,
 			}, 100),
 		),
 	)
-	md.Renderer().AddOptions(
+}
+
+func (e *Extender) extendRenderer(r renderer.Renderer) {
+	r.AddOptions(
 		renderer.WithNodeRenderers(
 			util.Prioritized(&Renderer{
-				Position: e.Position,
-				Unsafe:   e.Unsafe,
+				Unsafe:        e.Unsafe,
+				Sanitizer:     e.Sanitizer,
+				Placement:     e.Placement,
+				WrapperClass:  e.WrapperClass,
+				Spacing:       e.Spacing,
+				Accessibility: e.Accessibility,
+
+				AttributePolicy: e.AttributePolicy,
 			}, 100),
 		),
 	)
 }
+
+// _extendedOption is set on the parser and renderer
+// of Markdowns extended with an Extender.
+const _extendedOption = "go.abhg.dev/goldmark/anchor.extended"
+
+// markExtended is a parser and renderer option
+// that marks the parser or renderer as extended.
+// It records in extended whether it was already marked.
+type markExtended struct{ extended *bool }
+
+var (
+	_ parser.Option   = markExtended{}
+	_ renderer.Option = markExtended{}
+)
+
+func (o markExtended) SetParserOption(c *parser.Config) {
+	_, *o.extended = c.Options[_extendedOption]
+	c.Options[_extendedOption] = true
+}
+
+func (o markExtended) SetConfig(c *renderer.Config) {
+	_, *o.extended = c.Options[_extendedOption]
+	c.Options[_extendedOption] = true
+}
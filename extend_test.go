@@ -0,0 +1,86 @@
+package anchor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+)
+
+func TestExtender_extendTwice(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	newExtender := func() *Extender {
+		return &Extender{
+			Texter: texterFunc(func(*HeaderInfo) string {
+				calls++
+				return "#"
+			}),
+			IDPrefix:             "faq-",
+			RewriteFragmentLinks: true,
+			LevelOffset:          1,
+		}
+	}
+
+	ext := newExtender()
+	tests := []struct {
+		desc string
+		give []goldmark.Extender
+	}{
+		{desc: "same extender", give: []goldmark.Extender{ext, ext}},
+		{desc: "equal extenders", give: []goldmark.Extender{newExtender(), newExtender()}},
+		{
+			// Only the first Extender takes effect.
+			desc: "different extenders",
+			give: []goldmark.Extender{
+				newExtender(),
+				&Extender{
+					Texter:      Text("¶"),
+					Position:    Before,
+					IDPrefix:    "other-",
+					LevelOffset: 2,
+					Placement:   Wrapped,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		// Not parallel because of the shared calls counter.
+		t.Run(tt.desc, func(t *testing.T) {
+			calls = 0
+			md := goldmark.New(
+				goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+				goldmark.WithExtensions(tt.give...),
+			)
+
+			var buf bytes.Buffer
+			require.NoError(t, md.Convert([]byte("# Install\n\nSee [x](#install).\n"), &buf))
+			assert.Equal(t,
+				`<h2 id="faq-install">Install <a class="anchor" href="#faq-install">#</a></h2>`+"\n"+
+					`<p>See <a href="#faq-install">x</a>.</p>`+"\n",
+				buf.String())
+			assert.Equal(t, 1, calls, "anchor text must be computed once")
+		})
+	}
+}
+
+func TestExtender_extendOtherMarkdown(t *testing.T) {
+	t.Parallel()
+
+	ext := &Extender{Texter: Text("#")}
+	for _, src := range []string{"# Foo\n", "# Bar\n"} {
+		md := goldmark.New(
+			goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+			goldmark.WithExtensions(ext),
+		)
+
+		var buf bytes.Buffer
+		require.NoError(t, md.Convert([]byte(src), &buf))
+		assert.Contains(t, buf.String(), `<a class="anchor"`)
+	}
+}
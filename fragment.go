@@ -0,0 +1,47 @@
+package anchor
+
+const _upperhex = "0123456789ABCDEF"
+
+// escapeFragment percent-encodes an identifier
+// for use as the fragment of a URL.
+//
+// It encodes the characters in the fragment percent-encode set
+// of the WHATWG URL specification:
+// C0 controls, space, '"', '<', '>', '`', and bytes above '~'.
+// It also encodes '%' so that browsers, which percent-decode fragments
+// when looking for the target element, always resolve the fragment
+// to the exact identifier.
+//
+// See https://url.spec.whatwg.org/#fragment-percent-encode-set.
+func escapeFragment(id []byte) []byte {
+	n := 0
+	for _, c := range id {
+		if shouldEscapeFragment(c) {
+			n++
+		}
+	}
+	if n == 0 {
+		return id
+	}
+
+	out := make([]byte, 0, len(id)+2*n)
+	for _, c := range id {
+		if shouldEscapeFragment(c) {
+			out = append(out, '%', _upperhex[c>>4], _upperhex[c&0xf])
+		} else {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func shouldEscapeFragment(c byte) bool {
+	switch {
+	case c <= 0x20, c > '~':
+		return true
+	case c == '"', c == '<', c == '>', c == '`', c == '%':
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,99 @@
+package anchor
+
+import (
+	"bytes"
+	"html"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestEscapeFragment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give string
+		want string
+	}{
+		{desc: "empty", give: "", want: ""},
+		{desc: "plain", give: "foo-bar_baz.1", want: "foo-bar_baz.1"},
+		{desc: "space", give: "foo bar", want: "foo%20bar"},
+		{desc: "percent", give: "100%", want: "100%25"},
+		{desc: "percent escape", give: "a%20b", want: "a%2520b"},
+		{desc: "quotes", give: `"foo"<bar>` + "`", want: "%22foo%22%3Cbar%3E%60"},
+		{desc: "controls", give: "a\x00b\tc\x7f", want: "a%00b%09c%7F"},
+		{desc: "non-ascii", give: "мир", want: "%D0%BC%D0%B8%D1%80"},
+		{desc: "allowed punctuation", give: "a#b?c/d&e'f", want: "a#b?c/d&e'f"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, string(escapeFragment([]byte(tt.give))))
+		})
+	}
+}
+
+func FuzzEscapeFragment(f *testing.F) {
+	f.Add("foo")
+	f.Add("foo bar")
+	f.Add("100%")
+	f.Add("a%20b")
+	f.Add("мир")
+	f.Add(`"<>` + "`")
+	f.Add("\x00\x7f\xff")
+
+	f.Fuzz(func(t *testing.T, id string) {
+		frag := escapeFragment([]byte(id))
+
+		// The fragment must parse as a URL
+		// and decode back to the exact ID.
+		u, err := url.Parse("https://example.com/#" + string(frag))
+		require.NoError(t, err)
+		assert.Equal(t, id, u.Fragment)
+	})
+}
+
+var _hrefRegexp = regexp.MustCompile(`href="([^"]*)"`)
+
+func FuzzRenderer_href(f *testing.F) {
+	f.Add("foo")
+	f.Add("foo bar")
+	f.Add("a&b")
+	f.Add(`a"b`)
+	f.Add("a%20b")
+	f.Add("мир")
+
+	r := renderer.NewRenderer(
+		renderer.WithNodeRenderers(
+			util.Prioritized(&Renderer{}, 100),
+		),
+	)
+
+	f.Fuzz(func(t *testing.T, id string) {
+		if len(id) == 0 {
+			return
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, r.Render(&buf, nil /* src */, &Node{
+			ID:    []byte(id),
+			Value: []byte("#"),
+		}))
+
+		m := _hrefRegexp.FindStringSubmatch(buf.String())
+		require.NotNil(t, m, "no href in %q", buf.String())
+
+		u, err := url.Parse("https://example.com/" + html.UnescapeString(m[1]))
+		require.NoError(t, err)
+		assert.Equal(t, id, u.Fragment)
+	})
+}
@@ -0,0 +1,321 @@
+package anchor_test
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"go.abhg.dev/goldmark/anchor"
+	xhtml "golang.org/x/net/html"
+)
+
+// _fuzzClass is the class of anchors generated in fuzz tests.
+// It tells them apart from other links in the document.
+const _fuzzClass = "fuzz-anchor"
+
+// fuzzFlags holds Extender settings for fuzz tests packed into bits.
+type fuzzFlags uint16
+
+const (
+	fuzzBefore fuzzFlags = 1 << iota
+	fuzzPlacement1
+	fuzzPlacement2
+	fuzzLinks1
+	fuzzLinks2
+	fuzzUnsafe
+	fuzzAccessibility
+	fuzzHiddenText
+	fuzzNoWrap
+	fuzzRewriteFragmentLinks
+	fuzzStats
+)
+
+func (f fuzzFlags) has(flag fuzzFlags) bool { return f&flag != 0 }
+
+func (f fuzzFlags) extender(value, attr, idPrefix string, levelOffset int8) *anchor.Extender {
+	ext := &anchor.Extender{
+		Texter: anchor.Text(value),
+		Attributer: anchor.Attributes{
+			"class":     _fuzzClass,
+			"data-fuzz": attr,
+		},
+		IDPrefix:             idPrefix,
+		RewriteFragmentLinks: f.has(fuzzRewriteFragmentLinks),
+		LevelOffset:          int(levelOffset % 7),
+		Unsafe:               f.has(fuzzUnsafe),
+	}
+	if f.has(fuzzBefore) {
+		ext.Position = anchor.Before
+	}
+	switch {
+	case f.has(fuzzPlacement1) && f.has(fuzzPlacement2):
+		ext.Placement = anchor.Wrapped
+	case f.has(fuzzPlacement1):
+		ext.Placement = anchor.Outside
+	}
+	switch {
+	case f.has(fuzzLinks1):
+		ext.LinkPolicy = anchor.LinkSkip
+	case f.has(fuzzLinks2):
+		ext.LinkPolicy = anchor.LinkUnwrap
+	}
+	if f.has(fuzzAccessibility) {
+		ext.Accessibility = &anchor.Accessibility{
+			HiddenText: f.has(fuzzHiddenText),
+		}
+	}
+	if f.has(fuzzNoWrap) {
+		ext.NoWrap = true
+	}
+	if f.has(fuzzStats) {
+		ext.Stats = &anchor.Stats{ReadingTimeAttribute: true}
+	}
+	return ext
+}
+
+func FuzzExtender(f *testing.F) {
+	f.Add("# Foo\n\n## Bar\n", "¶", "x", "", uint16(0), int8(0))
+	f.Add("# Foo *bar* `baz`\n", "#", "", "doc-", uint16(fuzzBefore|fuzzAccessibility), int8(1))
+	f.Add("# [Foo](http://example.com)\n\n[see](#foo)\n", "<b>#</b>", "", "p-", uint16(fuzzLinks2|fuzzRewriteFragmentLinks), int8(0))
+	f.Add("# Foo {#a%b}\n\n# Foo\n", "&amp;", `"<>`, "", uint16(fuzzPlacement1|fuzzNoWrap), int8(-3))
+	f.Add("Foo\n===\n\n### Bar <em>baz</em>\n", `"><script>`, "", "", uint16(fuzzPlacement1|fuzzPlacement2|fuzzHiddenText|fuzzAccessibility), int8(5))
+	f.Add("# Foo Bar Baz\n\nSome text.\n\n```\ncode\n```\n", "🔗", "", "", uint16(fuzzNoWrap|fuzzBefore|fuzzStats), int8(0))
+	f.Add("#\n\n# <http://example.com>\n", "<", "", "#", uint16(fuzzUnsafe), int8(2))
+
+	f.Fuzz(func(t *testing.T, src, value, attr, idPrefix string, flags uint16, levelOffset int8) {
+		ff := fuzzFlags(flags)
+		md := goldmark.New(
+			goldmark.WithParserOptions(
+				parser.WithAutoHeadingID(),
+				parser.WithAttribute(),
+			),
+			goldmark.WithRendererOptions(html.WithXHTML()),
+			goldmark.WithExtensions(ff.extender(value, attr, idPrefix, levelOffset)),
+		)
+
+		doc := md.Parser().Parse(text.NewReader([]byte(src)))
+		checkAnchorCount(t, doc)
+
+		var buf bytes.Buffer
+		if err := md.Renderer().Render(&buf, []byte(src), doc); err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		if ff.has(fuzzUnsafe) {
+			// Unsafe anchor text may be arbitrary markup.
+			return
+		}
+
+		checkHTML(t, buf.Bytes(), value)
+	})
+}
+
+// checkAnchorCount verifies that no heading has more than one anchor.
+func checkAnchorCount(t *testing.T, doc ast.Node) {
+	t.Helper()
+
+	_ = ast.Walk(doc, func(n ast.Node, enter bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !enter || !ok {
+			return ast.WalkContinue, nil
+		}
+
+		var count int
+		_ = ast.Walk(h, func(n ast.Node, enter bool) (ast.WalkStatus, error) {
+			if _, ok := n.(*anchor.Node); ok && enter {
+				count++
+			}
+			return ast.WalkContinue, nil
+		})
+		if count > 1 {
+			t.Errorf("heading has %d anchors", count)
+		}
+		return ast.WalkSkipChildren, nil
+	})
+}
+
+// _voidElements are HTML elements that never have an end tag.
+var _voidElements = map[string]struct{}{
+	"br": {}, "hr": {}, "img": {}, "input": {},
+}
+
+// checkHTML verifies that the given HTML is well-formed,
+// that all anchors link to IDs in the document,
+// and that anchors hold the given value as text.
+func checkHTML(t *testing.T, out []byte, value string) {
+	t.Helper()
+
+	var (
+		stack   []string // open elements
+		ids     = make(map[string]struct{})
+		hrefs   []string
+		anchorI = -1 // index of the anchor in stack, if inside one
+		hiddenI = -1 // index of the visually hidden span in stack, if inside one
+		texts   []string
+	)
+
+	z := xhtml.NewTokenizer(bytes.NewReader(out))
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			if err := z.Err(); err != io.EOF {
+				t.Fatalf("tokenize: %v\n%s", err, out)
+			}
+			break
+		}
+
+		tok := z.Token()
+		switch tt {
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			attrs := make(map[string]string, len(tok.Attr))
+			for _, a := range tok.Attr {
+				if _, ok := attrs[a.Key]; ok {
+					t.Errorf("duplicate attribute %q in %v\n%s", a.Key, tok, out)
+				}
+				attrs[a.Key] = a.Val
+			}
+			if id, ok := attrs["id"]; ok {
+				ids[normalizeHTML(id)] = struct{}{}
+			}
+
+			if anchorI >= 0 && tok.Data != "span" {
+				t.Errorf("unexpected <%v> inside anchor:\n%s", tok.Data, out)
+			}
+
+			if tt == xhtml.SelfClosingTagToken {
+				break
+			}
+			if _, ok := _voidElements[tok.Data]; ok {
+				t.Errorf("void element <%v> not self-closing:\n%s", tok.Data, out)
+				break
+			}
+
+			if tok.Data == "a" && attrs["class"] == _fuzzClass {
+				if anchorI >= 0 {
+					t.Errorf("nested anchors:\n%s", out)
+				}
+				anchorI = len(stack)
+				hrefs = append(hrefs, attrs["href"])
+				texts = append(texts, "")
+			}
+			if anchorI >= 0 && tok.Data == "span" && attrs["aria-hidden"] != "true" {
+				hiddenI = len(stack)
+			}
+			stack = append(stack, tok.Data)
+
+		case xhtml.EndTagToken:
+			if len(stack) == 0 || stack[len(stack)-1] != tok.Data {
+				t.Fatalf("unexpected </%v>, open elements: %v\n%s", tok.Data, stack, out)
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == anchorI {
+				anchorI = -1
+			}
+			if len(stack) == hiddenI {
+				hiddenI = -1
+			}
+
+		case xhtml.TextToken:
+			if anchorI >= 0 && hiddenI < 0 {
+				texts[len(texts)-1] += tok.Data
+			}
+		}
+	}
+	if len(stack) > 0 {
+		t.Fatalf("unclosed elements: %v\n%s", stack, out)
+	}
+
+	for _, href := range hrefs {
+		fragment, ok := strings.CutPrefix(href, "#")
+		if !ok {
+			t.Errorf("href %q is not a fragment", href)
+			continue
+		}
+		id, err := url.PathUnescape(fragment)
+		if err != nil {
+			t.Errorf("href %q: %v", href, err)
+			continue
+		}
+		if _, ok := ids[normalizeHTML(id)]; !ok {
+			t.Errorf("href %q does not match any ID in:\n%s", href, out)
+		}
+	}
+
+	for _, got := range texts {
+		if normalizeHTML(got) != normalizeHTML(value) {
+			t.Errorf("anchor text %q, want %q:\n%s", got, value, out)
+		}
+	}
+}
+
+// normalizeHTML normalizes the given string
+// the same way the HTML tokenizer normalizes text and attribute values,
+// so that strings before and after tokenization can be compared.
+func normalizeHTML(s string) string {
+	return strings.NewReplacer(
+		"\r\n", "\n",
+		"\r", "\n",
+		"\x00", "\ufffd",
+	).Replace(s)
+}
+
+func FuzzAllowlistSanitizer(f *testing.F) {
+	f.Add(`<span class="icon">#</span>`)
+	f.Add(`<svg viewBox="0 0 16 16"><path d="M4 8h8"/></svg>`)
+	f.Add(`<img src="javascript:alert(1)" onerror="alert(1)">`)
+	f.Add(`<b><i>x</b></i><script>alert(1)</script>`)
+	f.Add(`<style><b>x</style><textarea></span></textarea>`)
+	f.Add(`<!-- x --><![CDATA[y]]><?php ?>`)
+
+	var s anchor.AllowlistSanitizer
+	f.Fuzz(func(t *testing.T, src string) {
+		got := s.Sanitize([]byte(src))
+		assert.Equal(t, string(got), string(s.Sanitize(got)),
+			"sanitizing must be idempotent")
+
+		var stack []string
+		z := xhtml.NewTokenizer(bytes.NewReader(got))
+		for {
+			tt := z.Next()
+			if tt == xhtml.ErrorToken {
+				break
+			}
+
+			tok := z.Token()
+			switch tt {
+			case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+				switch tok.Data {
+				case "script", "style", "iframe", "a":
+					t.Errorf("unexpected <%v>:\n%s", tok.Data, got)
+				}
+				for _, a := range tok.Attr {
+					if strings.HasPrefix(a.Key, "on") || a.Key == "href" {
+						t.Errorf("unexpected attribute %q:\n%s", a.Key, got)
+					}
+				}
+				if _, ok := _voidElements[tok.Data]; tt == xhtml.StartTagToken && !ok {
+					stack = append(stack, tok.Data)
+				}
+
+			case xhtml.EndTagToken:
+				if len(stack) == 0 || stack[len(stack)-1] != tok.Data {
+					t.Fatalf("unexpected </%v>, open elements: %v\n%s", tok.Data, stack, got)
+				}
+				stack = stack[:len(stack)-1]
+
+			case xhtml.CommentToken, xhtml.DoctypeToken:
+				t.Errorf("unexpected %v:\n%s", tt, got)
+			}
+		}
+		if len(stack) > 0 {
+			t.Fatalf("unclosed elements: %v\n%s", stack, got)
+		}
+	})
+}
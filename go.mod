@@ -7,6 +7,8 @@ toolchain go1.25.5
 require (
 	github.com/stretchr/testify v1.11.1
 	github.com/yuin/goldmark v1.7.16
+	golang.org/x/net v0.49.0
+	golang.org/x/text v0.33.0
 	golang.org/x/tools v0.40.0
 	gopkg.in/yaml.v3 v3.0.1
 )
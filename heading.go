@@ -0,0 +1,32 @@
+package anchor
+
+import (
+	"github.com/yuin/goldmark/ast"
+)
+
+// headingAnchor returns the first anchor node inside the given heading,
+// or nil if the heading does not have one.
+func headingAnchor(h ast.Node) *Node {
+	for c := h.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *Node:
+			return c
+		case *noWrap:
+			if n := headingAnchor(c); n != nil {
+				return n
+			}
+		}
+	}
+	return nil
+}
+
+// anchorHeading returns the node containing the given anchor node
+// that holds the heading text.
+// This is usually the heading itself.
+func anchorHeading(n *Node) ast.Node {
+	p := n.Parent()
+	if _, ok := p.(*noWrap); ok {
+		p = p.Parent()
+	}
+	return p
+}
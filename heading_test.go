@@ -0,0 +1,61 @@
+package anchor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestHeadingText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give string
+		want string
+	}{
+		{desc: "plain", give: "# Foo bar", want: "Foo bar"},
+		{desc: "emphasis", give: "# Foo *bar* __baz__", want: "Foo bar baz"},
+		{desc: "code span", give: "# Use `foo()`", want: "Use foo()"},
+		{desc: "link", give: "# [Foo](http://example.com) bar", want: "Foo bar"},
+		{desc: "autolink", give: "# See <http://example.com>", want: "See http://example.com"},
+		{desc: "raw html", give: "# Foo <b>bar</b>", want: "Foo bar"},
+		{desc: "image", give: "# ![alt text](foo.png) Foo", want: "alt text Foo"},
+		{desc: "empty", give: "#", want: ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			src := []byte(tt.give + "\n")
+			doc := goldmark.New().Parser().Parse(text.NewReader(src))
+			h, ok := doc.FirstChild().(*ast.Heading)
+			require.True(t, ok, "expected heading, got %T", doc.FirstChild())
+
+			// Anchors inside the heading must be ignored.
+			h.AppendChild(h, &Node{Value: []byte("#")})
+
+			assert.Equal(t, tt.want, string(headingText(h, src)))
+		})
+	}
+}
+
+func TestHeadingAnchor(t *testing.T) {
+	t.Parallel()
+
+	var h ast.Heading
+	assert.Nil(t, headingAnchor(&h))
+
+	h.AppendChild(&h, ast.NewString([]byte("foo")))
+	assert.Nil(t, headingAnchor(&h))
+
+	n := &Node{ID: []byte("foo")}
+	h.AppendChild(&h, n)
+	assert.Same(t, n, headingAnchor(&h))
+}
@@ -0,0 +1,192 @@
+package anchor
+
+import (
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+)
+
+// IDs generates identifiers for headers using a [Slugger].
+//
+// IDs implements [parser.IDs].
+// Use NewContext to build a [parser.Context] for each conversion
+// that generates IDs with this configuration.
+//
+//	ids := &anchor.IDs{
+//		Slugger:  &anchor.UnicodeSlugger{},
+//		Reserved: []string{"top", "content"},
+//	}
+//	err := md.Convert(src, &buf, parser.WithContext(ids.NewContext()))
+//
+// IDs keeps track of identifiers that are already in use,
+// and generates alternatives for duplicates.
+// When installing an IDs into a [parser.Context] directly
+// with [parser.WithIDs], use a new IDs for each conversion.
+type IDs struct {
+	// Slugger generates identifiers from header text.
+	//
+	// Defaults to a UnicodeSlugger with default settings.
+	Slugger Slugger
+
+	// Reserved is a list of identifiers that will never be generated,
+	// for example, IDs used by other elements on the page.
+	//
+	// Headers that would get a reserved ID
+	// get an alternative from Disambiguate instead.
+	Reserved []string
+
+	// Prefix is prepended to all generated identifiers.
+	//
+	// Prefix is not added to identifiers
+	// specified explicitly in the document.
+	// Use the IDPrefix option of [Transformer] or [Extender]
+	// to prefix those too.
+	// If both are set, generated identifiers get both prefixes.
+	Prefix string
+
+	// MaxLength is the maximum length of generated identifiers in bytes,
+	// including Prefix.
+	// Longer identifiers are truncated.
+	//
+	// Prefix is never truncated.
+	// If Prefix is MaxLength bytes or longer,
+	// generated identifiers are just the Prefix,
+	// with a suffix from Disambiguate for all but the first,
+	// so they are longer than MaxLength.
+	//
+	// Defaults to no limit.
+	MaxLength int
+
+	// Disambiguate generates an alternative for an identifier
+	// that is already in use.
+	// It's called with attempt numbers starting at 1
+	// until it returns an identifier that is not in use.
+	//
+	// Disambiguate should append to the identifier
+	// so that it can be truncated to fit MaxLength.
+	//
+	// Defaults to appending "-N" to the identifier
+	// where N is the attempt number.
+	Disambiguate func(id []byte, attempt int) []byte
+
+	values map[string]struct{}
+}
+
+var _ parser.IDs = (*IDs)(nil)
+
+// NewContext builds a new [parser.Context]
+// that generates IDs with a copy of this configuration.
+// Pass the context to Goldmark with [parser.WithContext].
+//
+//	md.Convert(src, &buf, parser.WithContext(ids.NewContext()))
+//
+// Use a new context for each conversion.
+// The same IDs may be used to build contexts concurrently.
+func (ids *IDs) NewContext(opts ...parser.ContextOption) parser.Context {
+	fresh := *ids
+	fresh.values = nil
+
+	opts = append(opts[:len(opts):len(opts)], parser.WithIDs(&fresh))
+	return parser.NewContext(opts...)
+}
+
+// Generate generates a new unique identifier for a node
+// with the given text.
+func (ids *IDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	slugger := ids.Slugger
+	if slugger == nil {
+		slugger = new(UnicodeSlugger)
+	}
+
+	slug := slugger.Slug(value)
+	if len(slug) == 0 {
+		if kind == ast.KindHeading {
+			slug = []byte("heading")
+		} else {
+			slug = []byte("id")
+		}
+	}
+
+	id := make([]byte, 0, len(ids.Prefix)+len(slug))
+	id = append(id, ids.Prefix...)
+	id = append(id, slug...)
+	id = ids.truncate(id, ids.MaxLength)
+
+	if !ids.used(id) {
+		ids.Put(id)
+		return id
+	}
+
+	disambiguate := ids.Disambiguate
+	if disambiguate == nil {
+		disambiguate = appendAttempt
+	}
+
+	for attempt := 1; ; attempt++ {
+		// Copy id because disambiguate may append to it.
+		alt := disambiguate(id[:len(id):len(id)], attempt)
+		if over := len(alt) - ids.MaxLength; ids.MaxLength > 0 && over > 0 {
+			// Make room for the suffix.
+			base := ids.truncate(id, len(id)-over)
+			alt = disambiguate(base[:len(base):len(base)], attempt)
+		}
+
+		if !ids.used(alt) {
+			ids.Put(alt)
+			return alt
+		}
+	}
+}
+
+// Put records the given identifier as used.
+//
+// Goldmark calls this for identifiers specified explicitly
+// in the document.
+func (ids *IDs) Put(value []byte) {
+	ids.init()
+	ids.values[string(value)] = struct{}{}
+}
+
+func (ids *IDs) init() {
+	if ids.values != nil {
+		return
+	}
+
+	ids.values = make(map[string]struct{}, len(ids.Reserved))
+	for _, id := range ids.Reserved {
+		ids.values[id] = struct{}{}
+	}
+}
+
+func (ids *IDs) used(id []byte) bool {
+	ids.init()
+	_, ok := ids.values[string(id)]
+	return ok
+}
+
+// truncate truncates id to at most n bytes,
+// without splitting characters or leaving a trailing '-'.
+// The Prefix is never truncated.
+func (ids *IDs) truncate(id []byte, n int) []byte {
+	if n <= 0 || len(id) <= n {
+		return id
+	}
+	n = max(n, len(ids.Prefix))
+
+	for n > len(ids.Prefix) && !utf8.RuneStart(id[n]) {
+		n--
+	}
+	for n > len(ids.Prefix) && id[n-1] == '-' {
+		n--
+	}
+	return id[:n]
+}
+
+func appendAttempt(id []byte, attempt int) []byte {
+	if len(id) > 0 && id[len(id)-1] != '-' {
+		id = append(id, '-')
+	}
+	return strconv.AppendInt(id, int64(attempt), 10)
+}
@@ -0,0 +1,216 @@
+package anchor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+)
+
+func TestIDs(t *testing.T) {
+	t.Parallel()
+
+	var ids IDs
+	assert.Equal(t, "foo", string(ids.Generate([]byte("Foo"), ast.KindHeading)))
+	assert.Equal(t, "foo-1", string(ids.Generate([]byte("Foo"), ast.KindHeading)))
+	assert.Equal(t, "foo-2", string(ids.Generate([]byte("foo"), ast.KindHeading)))
+	assert.Equal(t, "heading", string(ids.Generate([]byte("!"), ast.KindHeading)))
+	assert.Equal(t, "id", string(ids.Generate(nil, ast.KindParagraph)))
+
+	ids.Put([]byte("bar"))
+	assert.Equal(t, "bar-1", string(ids.Generate([]byte("Bar"), ast.KindHeading)))
+}
+
+func TestIDs_convert(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithAttribute(),
+		),
+		goldmark.WithExtensions(&Extender{}),
+	)
+
+	ctx := parser.NewContext(parser.WithIDs(&IDs{
+		Slugger: &UnicodeSlugger{Transliterator: Cyrillic()},
+	}))
+
+	var buf bytes.Buffer
+	require.NoError(t, md.Convert(
+		[]byte("# Привет\n\n## Привет\n\n## Мир {#mir}\n\n## Mir\n"),
+		&buf, parser.WithContext(ctx)))
+	assert.Equal(t,
+		`<h1 id="privet">Привет <a class="anchor" href="#privet">¶</a></h1>`+"\n"+
+			`<h2 id="privet-1">Привет <a class="anchor" href="#privet-1">¶</a></h2>`+"\n"+
+			`<h2 id="mir">Мир <a class="anchor" href="#mir">¶</a></h2>`+"\n"+
+			`<h2 id="mir-1">Mir <a class="anchor" href="#mir-1">¶</a></h2>`+"\n",
+		buf.String())
+}
+
+func TestIDs_unicodeHref(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithExtensions(&Extender{}),
+	)
+
+	ctx := parser.NewContext(parser.WithIDs(new(IDs)))
+
+	var buf bytes.Buffer
+	require.NoError(t, md.Convert([]byte("# Мир\n"), &buf, parser.WithContext(ctx)))
+	assert.Equal(t,
+		`<h1 id="мир">Мир <a class="anchor" href="#%D0%BC%D0%B8%D1%80">¶</a></h1>`+"\n",
+		buf.String())
+}
+
+func TestIDs_options(t *testing.T) {
+	t.Parallel()
+
+	type generate struct {
+		give string
+		want string
+	}
+
+	tests := []struct {
+		desc string
+		ids  IDs
+		put  []string
+		give []generate
+	}{
+		{
+			desc: "reserved",
+			ids:  IDs{Reserved: []string{"top", "content"}},
+			give: []generate{
+				{"Top", "top-1"},
+				{"Content", "content-1"},
+				{"Other", "other"},
+			},
+		},
+		{
+			desc: "prefix",
+			ids:  IDs{Prefix: "faq-"},
+			put:  []string{"explicit"},
+			give: []generate{
+				{"Installation", "faq-installation"},
+				{"Installation", "faq-installation-1"},
+				{"Explicit", "faq-explicit"},
+			},
+		},
+		{
+			desc: "max length",
+			ids:  IDs{MaxLength: 10},
+			give: []generate{
+				{"Short", "short"},
+				{"A very long header", "a-very-lon"},
+				{"A very long header", "a-very-l-1"},
+				{"Abcdefghi jk", "abcdefghi"},
+				{"Aпривет", "aприв"},
+			},
+		},
+		{
+			desc: "max length with prefix",
+			ids:  IDs{Prefix: "section-", MaxLength: 10},
+			give: []generate{
+				{"Foo", "section-fo"},
+				{"Foo", "section-1"},
+			},
+		},
+		{
+			desc: "prefix longer than max length",
+			ids:  IDs{Prefix: "abcd", MaxLength: 3},
+			give: []generate{
+				{"Foo", "abcd"},
+				{"Foo", "abcd-1"},
+				{"Bar", "abcd-2"},
+			},
+		},
+		{
+			desc: "disambiguate",
+			ids: IDs{
+				Disambiguate: func(id []byte, attempt int) []byte {
+					return append(id, strings.Repeat("_", attempt)...)
+				},
+			},
+			give: []generate{
+				{"Foo", "foo"},
+				{"Foo", "foo_"},
+				{"Foo", "foo__"},
+			},
+		},
+		{
+			desc: "slug func",
+			ids: IDs{
+				Slugger: SlugFunc(bytes.ToUpper),
+			},
+			give: []generate{
+				{"foo", "FOO"},
+				{"foo", "FOO-1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ids := tt.ids
+			for _, id := range tt.put {
+				ids.Put([]byte(id))
+			}
+
+			for _, g := range tt.give {
+				got := ids.Generate([]byte(g.give), ast.KindHeading)
+				assert.Equal(t, g.want, string(got), "Generate(%q)", g.give)
+			}
+		})
+	}
+}
+
+func TestIDs_NewContext(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithExtensions(&Extender{}),
+	)
+
+	ids := &IDs{Reserved: []string{"top"}}
+
+	// Each context gets its own set of used IDs.
+	for i := 0; i < 2; i++ {
+		var buf bytes.Buffer
+		require.NoError(t, md.Convert([]byte("# Top\n"), &buf, parser.WithContext(ids.NewContext())))
+		assert.Equal(t,
+			`<h1 id="top-1">Top <a class="anchor" href="#top-1">¶</a></h1>`+"\n",
+			buf.String())
+	}
+}
+
+func TestIDs_withIDPrefix(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(
+		goldmark.WithParserOptions(parser.WithAutoHeadingID(), parser.WithAttribute()),
+		goldmark.WithExtensions(&Extender{IDPrefix: "faq-"}),
+	)
+
+	ids := &IDs{Prefix: "doc-"}
+
+	// Generated IDs get both prefixes,
+	// and explicit IDs get only IDPrefix.
+	var buf bytes.Buffer
+	src := "# Install\n\n# Usage {#use}\n"
+	require.NoError(t, md.Convert([]byte(src), &buf, parser.WithContext(ids.NewContext())))
+	assert.Equal(t,
+		`<h1 id="faq-doc-install">Install <a class="anchor" href="#faq-doc-install">¶</a></h1>`+"\n"+
+			`<h1 id="faq-use">Usage <a class="anchor" href="#faq-use">¶</a></h1>`+"\n",
+		buf.String())
+}
@@ -0,0 +1,10 @@
+// Package emojitest verifies that goldmark-anchor recognizes
+// emoji nodes from goldmark-emoji.
+//
+// goldmark-anchor inspects these nodes with reflection
+// so that it does not depend on goldmark-emoji.
+// The tests in this module fail
+// if goldmark-emoji changes the shape of its nodes.
+// They live in a separate module
+// to keep goldmark-emoji out of goldmark-anchor's go.mod.
+package emojitest
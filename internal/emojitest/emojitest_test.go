@@ -0,0 +1,43 @@
+package emojitest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	emoji "github.com/yuin/goldmark-emoji"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+	"go.abhg.dev/goldmark/anchor"
+)
+
+func TestPlainText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc  string
+		emoji anchor.EmojiPolicy
+		want  string
+	}{
+		{desc: "keep", emoji: anchor.EmojiKeep, want: "Deploying 🚀 fast 🎉"},
+		{desc: "strip", emoji: anchor.EmojiStrip, want: "Deploying fast"},
+		{desc: "shortcode", emoji: anchor.EmojiShortcode, want: "Deploying rocket fast tada"},
+	}
+
+	md := goldmark.New(goldmark.WithExtensions(emoji.Emoji))
+	src := []byte("# Deploying :rocket: fast :tada:\n")
+	doc := md.Parser().Parse(text.NewReader(src))
+	h, ok := doc.FirstChild().(*ast.Heading)
+	require.True(t, ok, "expected heading, got %T", doc.FirstChild())
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := anchor.PlainText{Emoji: tt.emoji}
+			assert.Equal(t, tt.want, string(p.Text(h, src)))
+		})
+	}
+}
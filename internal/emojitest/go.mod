@@ -0,0 +1,22 @@
+module go.abhg.dev/goldmark/anchor/internal/emojitest
+
+go 1.24.0
+
+toolchain go1.25.5
+
+replace go.abhg.dev/goldmark/anchor => ../../
+
+require (
+	github.com/stretchr/testify v1.11.1
+	github.com/yuin/goldmark v1.7.16
+	github.com/yuin/goldmark-emoji v1.0.6
+	go.abhg.dev/goldmark/anchor v0.2.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.49.0 // indirect
+	golang.org/x/text v0.33.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,80 @@
+package anchor
+
+import (
+	"github.com/yuin/goldmark/ast"
+)
+
+// LinkPolicy specifies how [Transformer] handles headers
+// that contain links.
+//
+// Anchors are links themselves,
+// so a header that contains a link will have two adjacent links
+// which may be confusing to readers and assistive technologies.
+type LinkPolicy int
+
+//go:generate stringer -type LinkPolicy
+
+const (
+	// LinkKeep keeps links inside headers as-is,
+	// and places the anchor next to them.
+	// The anchor is never placed inside a link.
+	//
+	// This is the default.
+	LinkKeep LinkPolicy = iota
+
+	// LinkSkip does not generate anchors for headers that contain links.
+	LinkSkip
+
+	// LinkUnwrap replaces links inside headers with their contents,
+	// leaving the anchor as the only link in the header.
+	LinkUnwrap
+)
+
+// hasLink reports whether the given node contains a link
+// or an autolink.
+func hasLink(n ast.Node) bool {
+	var found bool
+	_ = ast.Walk(n, func(n ast.Node, enter bool) (ast.WalkStatus, error) {
+		if !enter {
+			return ast.WalkContinue, nil
+		}
+
+		switch n.(type) {
+		case *ast.Link, *ast.AutoLink:
+			found = true
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	// Walk never fails because the walker never returns an error.
+
+	return found
+}
+
+// unwrapLinks replaces all links and autolinks inside the given node
+// with their contents.
+func unwrapLinks(parent ast.Node, src []byte) {
+	for c := parent.FirstChild(); c != nil; {
+		next := c.NextSibling()
+
+		switch c := c.(type) {
+		case *ast.Link:
+			// Links can't contain other links
+			// so we don't need to recurse into the moved children.
+			for gc := c.FirstChild(); gc != nil; {
+				gnext := gc.NextSibling()
+				parent.InsertBefore(parent, c, gc)
+				gc = gnext
+			}
+			parent.RemoveChild(parent, c)
+
+		case *ast.AutoLink:
+			parent.ReplaceChild(parent, c, ast.NewString(c.Label(src)))
+
+		default:
+			unwrapLinks(c, src)
+		}
+
+		c = next
+	}
+}
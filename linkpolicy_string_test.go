@@ -0,0 +1,31 @@
+package anchor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkPolicy_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give LinkPolicy
+		want string
+	}{
+		{desc: "keep", give: LinkKeep, want: "LinkKeep"},
+		{desc: "skip", give: LinkSkip, want: "LinkSkip"},
+		{desc: "unwrap", give: LinkUnwrap, want: "LinkUnwrap"},
+		{desc: "unknown", give: 42, want: "LinkPolicy(42)"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.give.String())
+		})
+	}
+}
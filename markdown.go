@@ -0,0 +1,205 @@
+package anchor
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// MarkdownText builds a Texter that uses inline Markdown
+// as the anchor text.
+// The Markdown is rendered by goldmark as part of the document,
+// so it does not need Unsafe.
+//
+//	anchor.Extender{
+//		Texter: anchor.MarkdownText("*§*"),
+//	}
+//
+// Emphasis, code spans, and images are supported.
+// Links are replaced with their text
+// because anchors can't contain other links,
+// and raw HTML is dropped.
+//
+// If s is not a single paragraph of Markdown,
+// it's used as plain text.
+// For example, "#" is a heading in Markdown, so it's used as-is.
+func MarkdownText(s string) NodeTexter {
+	src := []byte(s)
+	t := &markdownTexter{text: src}
+	if p := parseParagraph(src); p != nil {
+		var w plainTextWriter
+		w.WriteNode(p, src)
+		t.text = w.Bytes()
+		t.nodes = detachChildren(p, src)
+	}
+	return t
+}
+
+type markdownTexter struct {
+	text  []byte     // plain text of the Markdown
+	nodes []ast.Node // detached nodes; copied for each header
+}
+
+var _ NodeTexter = (*markdownTexter)(nil)
+
+func (t *markdownTexter) AnchorText(*HeaderInfo) []byte {
+	return t.text
+}
+
+func (t *markdownTexter) AnchorNodes(*HeaderInfo) []ast.Node {
+	// Each anchor needs its own copy of the nodes
+	// because a node can only have one parent.
+	var nodes []ast.Node
+	for _, n := range t.nodes {
+		nodes = append(nodes, cloneInline(n)...)
+	}
+	return nodes
+}
+
+// parseParagraph parses the given Markdown,
+// returning nil if it's not a single paragraph.
+func parseParagraph(src []byte) *ast.Paragraph {
+	doc := goldmark.DefaultParser().Parse(text.NewReader(src))
+	p, ok := doc.FirstChild().(*ast.Paragraph)
+	if !ok || p.NextSibling() != nil {
+		return nil
+	}
+	return p
+}
+
+// detachChildren removes the children of the given node,
+// and returns them converted with detachInline.
+func detachChildren(n ast.Node, src []byte) []ast.Node {
+	var children []ast.Node
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		children = append(children, c)
+	}
+	n.RemoveChildren(n)
+
+	var nodes []ast.Node
+	for _, c := range children {
+		nodes = append(nodes, detachInline(c, src)...)
+	}
+	return nodes
+}
+
+// detachInline converts an inline node parsed from src
+// into nodes that don't refer to src,
+// so that they can be rendered as part of another document.
+func detachInline(n ast.Node, src []byte) []ast.Node {
+	switch n := n.(type) {
+	case *ast.Text:
+		value := n.Value(src)
+		if n.SoftLineBreak() || n.HardLineBreak() {
+			value = append(slices.Clip(value), ' ')
+		}
+		s := ast.NewString(value)
+		s.SetRaw(n.IsRaw())
+		return []ast.Node{s}
+
+	case *ast.CodeSpan:
+		var value []byte
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			if t, ok := c.(*ast.Text); ok {
+				// Like goldmark, replace trailing newlines with spaces.
+				v := t.Segment.Value(src)
+				if v, ok := bytes.CutSuffix(v, []byte("\n")); ok {
+					value = append(append(value, v...), ' ')
+				} else {
+					value = append(value, v...)
+				}
+			}
+		}
+		return []ast.Node{&codeSpan{Value: value}}
+
+	case *ast.AutoLink:
+		return []ast.Node{ast.NewString(n.Label(src))}
+
+	case *ast.RawHTML:
+		return nil
+
+	case *ast.Link:
+		return detachChildren(n, src)
+
+	default:
+		// Emphasis, images, and other nodes that don't refer to src
+		// are kept with their children converted.
+		for _, c := range detachChildren(n, src) {
+			n.AppendChild(n, c)
+		}
+		return []ast.Node{n}
+	}
+}
+
+// cloneInline returns a deep copy of a node returned by detachInline.
+//
+// Nodes of unknown types are replaced with copies of their children.
+func cloneInline(n ast.Node) []ast.Node {
+	var c ast.Node
+	switch n := n.(type) {
+	case *ast.String:
+		s := ast.NewString(n.Value)
+		s.SetRaw(n.IsRaw())
+		s.SetCode(n.IsCode())
+		c = s
+	case *codeSpan:
+		c = &codeSpan{Value: n.Value}
+	case *ast.Emphasis:
+		c = ast.NewEmphasis(n.Level)
+	case *ast.Image:
+		link := ast.NewLink()
+		link.Destination = n.Destination
+		link.Title = n.Title
+		c = ast.NewImage(link)
+	}
+
+	var children []ast.Node
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		children = append(children, cloneInline(child)...)
+	}
+	if c == nil {
+		return children
+	}
+
+	for _, attr := range n.Attributes() {
+		c.SetAttribute(attr.Name, attr.Value)
+	}
+	for _, child := range children {
+		c.AppendChild(c, child)
+	}
+	return []ast.Node{c}
+}
+
+// _kindCodeSpan is the NodeKind used by codeSpan nodes.
+var _kindCodeSpan = ast.NewNodeKind("AnchorCodeSpan")
+
+// codeSpan is a code span in anchor text.
+//
+// It's used instead of ast.CodeSpan because the text of ast.CodeSpan
+// must be part of the document's source.
+type codeSpan struct {
+	ast.BaseInline
+
+	Value []byte
+}
+
+func (*codeSpan) Kind() ast.NodeKind { return _kindCodeSpan }
+
+func (n *codeSpan) Dump(src []byte, level int) {
+	ast.DumpHelper(n, src, level, map[string]string{
+		"Value": string(n.Value),
+	}, nil)
+}
+
+func (r *Renderer) renderCodeSpan(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<code>")
+		_, _ = w.Write(util.EscapeHTML(node.(*codeSpan).Value))
+		_, _ = w.WriteString("</code>")
+	}
+	return ast.WalkSkipChildren, nil
+}
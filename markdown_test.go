@@ -0,0 +1,132 @@
+package anchor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestMarkdownText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		give      string
+		wantText  string
+		wantNodes bool
+	}{
+		{give: "*§*", wantText: "§", wantNodes: true},
+		{give: "`#` [link](/x)", wantText: "# link", wantNodes: true},
+		{give: "**a *b*** ![c](/c.svg)", wantText: "a b c", wantNodes: true},
+		{give: "#", wantText: "#"},
+		{give: "a\n\nb", wantText: "a\n\nb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			t.Parallel()
+
+			texter := MarkdownText(tt.give)
+			assert.Equal(t, tt.wantText, string(texter.AnchorText(nil)))
+
+			first := texter.AnchorNodes(nil)
+			if !tt.wantNodes {
+				assert.Empty(t, first)
+				return
+			}
+
+			require.NotEmpty(t, first)
+			second := texter.AnchorNodes(nil)
+			require.Len(t, second, len(first))
+			for i, n := range first {
+				assert.Nil(t, n.Parent(), "node %d must not have a parent", i)
+				assertNotShared(t, n, second[i])
+			}
+		})
+	}
+}
+
+// assertNotShared asserts that two copies of a tree of nodes
+// have the same shape but no nodes in common.
+func assertNotShared(t *testing.T, a, b ast.Node) {
+	t.Helper()
+
+	assert.NotSame(t, a, b, "%v must be copied", a.Kind())
+	assert.Equal(t, a.Kind(), b.Kind())
+	require.Equal(t, a.ChildCount(), b.ChildCount(), "children of %v", a.Kind())
+	for ac, bc := a.FirstChild(), b.FirstChild(); ac != nil; ac, bc = ac.NextSibling(), bc.NextSibling() {
+		assertNotShared(t, ac, bc)
+	}
+}
+
+func TestTransform_nodeTexter(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("# Foo\n")
+	ctx := parser.NewContext()
+	md := goldmark.New(goldmark.WithParserOptions(parser.WithAutoHeadingID()))
+	doc := md.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	tr := &Transformer{Texter: nodeTexterFunc(func(*HeaderInfo) []ast.Node {
+		return []ast.Node{ast.NewString([]byte("a")), ast.NewString([]byte("b"))}
+	})}
+	tr.Transform(doc.(*ast.Document), text.NewReader(src), ctx)
+	// Re-transforming must replace the children, not add to them.
+	tr.Transform(doc.(*ast.Document), text.NewReader(src), ctx)
+
+	nodes := Nodes(ctx)
+	require.Len(t, nodes, 1)
+	assert.Equal(t, 2, nodes[0].ChildCount())
+	assert.Equal(t, "ab", string(nodes[0].Value),
+		"Value must be the plain text of the children")
+}
+
+func TestRenderer_childrenNotFirstOrLast(t *testing.T) {
+	t.Parallel()
+
+	// An anchor with children placed outside the heading
+	// in the middle of the heading falls back to its Value.
+	h := ast.NewHeading(1)
+	h.SetAttributeString("id", []byte("foo"))
+	n := &Node{ID: []byte("foo"), Value: []byte("#")}
+	n.AppendChild(n, ast.NewString([]byte("child")))
+	h.AppendChild(h, ast.NewString([]byte("Foo")))
+	h.AppendChild(h, n)
+	h.AppendChild(h, ast.NewString([]byte("Bar")))
+
+	r := renderer.NewRenderer(
+		renderer.WithNodeRenderers(
+			util.Prioritized(&Renderer{Placement: Outside}, 100),
+			util.Prioritized(stringRenderer{}, 1000),
+		),
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Render(&buf, nil, h))
+	assert.Equal(t, `<h1 id="foo">FooBar</h1><a href="#foo">#</a>`+"\n", buf.String())
+}
+
+type nodeTexterFunc func(*HeaderInfo) []ast.Node
+
+func (nodeTexterFunc) AnchorText(*HeaderInfo) []byte { return nil }
+
+func (f nodeTexterFunc) AnchorNodes(i *HeaderInfo) []ast.Node { return f(i) }
+
+// stringRenderer renders ast.String nodes as-is.
+type stringRenderer struct{}
+
+func (stringRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindString, func(w util.BufWriter, _ []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			_, _ = w.Write(n.(*ast.String).Value)
+		}
+		return ast.WalkContinue, nil
+	})
+}
@@ -0,0 +1,72 @@
+package anchor
+
+import (
+	"math"
+
+	"github.com/yuin/goldmark/parser"
+)
+
+// Metadata keys that override anchor settings for a single document.
+const (
+	_metaAnchors = "anchors"       // bool
+	_metaText    = "anchor_text"   // string
+	_metaLevels  = "anchor_levels" // []int
+)
+
+// MetadataFunc retrieves metadata for the document being converted,
+// for example, from its front matter.
+//
+// It's compatible with the Get function of goldmark-meta.
+//
+//	anchor.Extender{
+//		Metadata: meta.Get,
+//	}
+type MetadataFunc func(parser.Context) map[string]any
+
+// applyMetadata overrides the transformation settings
+// with settings from the document metadata.
+// It reports false if anchors are disabled for the document.
+//
+// Values of unexpected types are ignored.
+func (t *transform) applyMetadata(meta map[string]any) bool {
+	if enabled, ok := meta[_metaAnchors].(bool); ok && !enabled {
+		return false
+	}
+
+	if text, ok := meta[_metaText].(string); ok {
+		t.Texter = Text(text)
+	}
+
+	if levels, ok := meta[_metaLevels].([]any); ok {
+		t.Levels = make([]int, 0, len(levels))
+		for _, v := range levels {
+			if level, ok := metadataInt(v); ok {
+				t.Levels = append(t.Levels, level)
+			}
+		}
+
+		// An empty list means no levels get anchors,
+		// not the default of all levels.
+		if len(t.Levels) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// metadataInt converts a number decoded from front matter to an int.
+func metadataInt(v any) (int, bool) {
+	switch v := v.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case uint64:
+		return int(v), v <= math.MaxInt
+	case float64:
+		return int(v), v == math.Trunc(v)
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,255 @@
+package anchor
+
+import (
+	"github.com/yuin/goldmark/parser"
+)
+
+// ContextOption overrides a setting of the [Transformer] or [Renderer]
+// for a single conversion.
+//
+// Use [WithContextOptions] to attach ContextOptions
+// to the [parser.Context] of a conversion.
+type ContextOption func(*contextOptions)
+
+// contextOptions holds settings overridden for a single conversion.
+// Nil fields are not overridden.
+type contextOptions struct {
+	texter               Texter
+	attributer           Attributer
+	position             *Position
+	linkPolicy           *LinkPolicy
+	levels               *[]int
+	idPrefix             *string
+	rewriteFragmentLinks *bool
+	levelOffset          *int
+	stats                **Stats
+	emoji                *EmojiPolicy
+	emojiName            func(string) string
+	noWrap               *bool
+
+	// render is attached to anchor nodes
+	// for the Renderer to read.
+	render renderOptions
+}
+
+// renderOptions holds Renderer settings overridden for a single conversion.
+// Nil fields are not overridden.
+type renderOptions struct {
+	unsafe          *bool
+	sanitizer       *Sanitizer
+	wrapperClass    *string
+	spacing         **Spacing
+	accessibility   **Accessibility
+	attributePolicy **AttributePolicy
+}
+
+var _contextOptionsKey = parser.NewContextKey()
+
+// WithContextOptions overrides settings of the [Transformer] and [Renderer]
+// for the conversion that uses the given [parser.Context].
+// Use this to vary anchor settings between conversions
+// that share the same [goldmark.Markdown].
+//
+// The Renderer's Placement can't be overridden
+// because it determines which nodes the Renderer handles.
+// All other settings of the Transformer and Renderer can be.
+//
+//	ctx := parser.NewContext()
+//	anchor.WithContextOptions(ctx,
+//		anchor.WithTexter(anchor.Text("#")),
+//		anchor.WithPosition(anchor.Before),
+//	)
+//	err := md.Convert(src, &buf, parser.WithContext(ctx))
+//
+// Calling WithContextOptions multiple times on the same context
+// combines the options, with later options taking precedence.
+// Settings from document metadata (see [Transformer.Metadata])
+// take precedence over these options.
+func WithContextOptions(pc parser.Context, opts ...ContextOption) {
+	var co contextOptions
+	if prev := getContextOptions(pc); prev != nil {
+		co = *prev
+	}
+	for _, opt := range opts {
+		opt(&co)
+	}
+	pc.Set(_contextOptionsKey, &co)
+}
+
+func getContextOptions(pc parser.Context) *contextOptions {
+	co, _ := pc.Get(_contextOptionsKey).(*contextOptions)
+	return co
+}
+
+// WithTexter overrides the Texter for a conversion.
+func WithTexter(t Texter) ContextOption {
+	return func(co *contextOptions) { co.texter = t }
+}
+
+// WithAttributer overrides the Attributer for a conversion.
+func WithAttributer(a Attributer) ContextOption {
+	return func(co *contextOptions) { co.attributer = a }
+}
+
+// WithPosition overrides the Position of anchors for a conversion.
+func WithPosition(p Position) ContextOption {
+	return func(co *contextOptions) { co.position = &p }
+}
+
+// WithLinkPolicy overrides the LinkPolicy for a conversion.
+func WithLinkPolicy(p LinkPolicy) ContextOption {
+	return func(co *contextOptions) { co.linkPolicy = &p }
+}
+
+// WithLevels overrides the header levels that get anchors
+// for a conversion.
+// Call it with no arguments to generate anchors for all levels.
+func WithLevels(levels ...int) ContextOption {
+	return func(co *contextOptions) { co.levels = &levels }
+}
+
+// WithIDPrefix overrides the IDPrefix for a conversion.
+func WithIDPrefix(prefix string) ContextOption {
+	return func(co *contextOptions) { co.idPrefix = &prefix }
+}
+
+// WithLevelOffset overrides the LevelOffset for a conversion.
+func WithLevelOffset(offset int) ContextOption {
+	return func(co *contextOptions) { co.levelOffset = &offset }
+}
+
+// WithRewriteFragmentLinks overrides whether links to headers
+// are updated to use the IDPrefix for a conversion.
+func WithRewriteFragmentLinks(rewrite bool) ContextOption {
+	return func(co *contextOptions) { co.rewriteFragmentLinks = &rewrite }
+}
+
+// WithStats overrides the Stats for a conversion.
+// Pass nil to disable statistics.
+func WithStats(stats *Stats) ContextOption {
+	return func(co *contextOptions) { co.stats = &stats }
+}
+
+// WithEmoji overrides the EmojiPolicy for a conversion.
+func WithEmoji(p EmojiPolicy) ContextOption {
+	return func(co *contextOptions) { co.emoji = &p }
+}
+
+// WithEmojiName overrides the function that names emoji
+// written in header text for a conversion.
+// See [PlainText.EmojiName] for details.
+func WithEmojiName(name func(emoji string) string) ContextOption {
+	return func(co *contextOptions) { co.emojiName = name }
+}
+
+// WithNoWrap overrides whether anchors are kept on the same line
+// as the word of the header next to them for a conversion.
+// Don't use this if anchors are placed outside headings.
+func WithNoWrap(noWrap bool) ContextOption {
+	return func(co *contextOptions) { co.noWrap = &noWrap }
+}
+
+// WithUnsafe overrides whether anchor text is rendered without escaping
+// for a conversion.
+func WithUnsafe(unsafe bool) ContextOption {
+	return func(co *contextOptions) { co.render.unsafe = &unsafe }
+}
+
+// WithSanitizer overrides the Sanitizer for anchor text
+// for a conversion.
+// Pass nil to escape anchor text.
+func WithSanitizer(s Sanitizer) ContextOption {
+	return func(co *contextOptions) { co.render.sanitizer = &s }
+}
+
+// WithWrapperClass overrides the class of the <div> that wraps headings
+// for a conversion.
+func WithWrapperClass(class string) ContextOption {
+	return func(co *contextOptions) { co.render.wrapperClass = &class }
+}
+
+// WithSpacing overrides the Spacing between headers and anchors
+// for a conversion.
+// Pass nil to use the default spacing.
+func WithSpacing(s *Spacing) ContextOption {
+	return func(co *contextOptions) { co.render.spacing = &s }
+}
+
+// WithAccessibility overrides the Accessibility settings
+// for a conversion.
+// Pass nil to render anchors without additional markup.
+func WithAccessibility(a *Accessibility) ContextOption {
+	return func(co *contextOptions) { co.render.accessibility = &a }
+}
+
+// WithAttributePolicy overrides the AttributePolicy
+// for a conversion.
+// Pass nil to apply only the rules that always apply.
+func WithAttributePolicy(p *AttributePolicy) ContextOption {
+	return func(co *contextOptions) { co.render.attributePolicy = &p }
+}
+
+// apply overrides the settings of the given transformation.
+func (co *contextOptions) apply(t *transform) {
+	if co.texter != nil {
+		t.Texter = co.texter
+	}
+	if co.attributer != nil {
+		t.Attributer = co.attributer
+	}
+	if co.position != nil {
+		t.Position = *co.position
+	}
+	if co.linkPolicy != nil {
+		t.LinkPolicy = *co.linkPolicy
+	}
+	if co.levels != nil {
+		t.Levels = *co.levels
+	}
+	if co.idPrefix != nil {
+		t.IDPrefix = *co.idPrefix
+	}
+	if co.rewriteFragmentLinks != nil {
+		t.RewriteFragmentLinks = *co.rewriteFragmentLinks
+	}
+	if co.levelOffset != nil {
+		t.LevelOffset = *co.levelOffset
+	}
+	if co.stats != nil {
+		t.Stats = *co.stats
+	}
+	if co.emoji != nil {
+		t.PlainText.Emoji = *co.emoji
+	}
+	if co.emojiName != nil {
+		t.PlainText.EmojiName = co.emojiName
+	}
+	if co.noWrap != nil {
+		t.NoWrap = *co.noWrap
+	}
+	if co.render != (renderOptions{}) {
+		t.render = &co.render
+	}
+}
+
+// apply overrides the settings of the given Renderer.
+func (ro *renderOptions) apply(r *Renderer) {
+	if ro.unsafe != nil {
+		r.Unsafe = *ro.unsafe
+	}
+	if ro.sanitizer != nil {
+		r.Sanitizer = *ro.sanitizer
+	}
+	if ro.wrapperClass != nil {
+		r.WrapperClass = *ro.wrapperClass
+	}
+	if ro.spacing != nil {
+		r.Spacing = *ro.spacing
+	}
+	if ro.accessibility != nil {
+		r.Accessibility = *ro.accessibility
+	}
+	if ro.attributePolicy != nil {
+		r.AttributePolicy = *ro.attributePolicy
+	}
+}
@@ -0,0 +1,246 @@
+package anchor
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+)
+
+func TestWithContextOptions(t *testing.T) {
+	t.Parallel()
+
+	const src = "# Foo\n\n## [Bar](http://example.com) {#bar}\n"
+
+	tests := []struct {
+		desc string
+		give []ContextOption
+		want string
+	}{
+		{
+			desc: "none",
+			want: `<h1 id="foo">Foo <a class="anchor" href="#foo">¶</a></h1>` + "\n" +
+				`<h2 id="bar"><a href="http://example.com">Bar</a> <a class="anchor" href="#bar">¶</a></h2>` + "\n",
+		},
+		{
+			desc: "texter and position",
+			give: []ContextOption{
+				WithTexter(Text("#")),
+				WithPosition(Before),
+			},
+			want: `<h1 id="foo"><a class="anchor" href="#foo">#</a> Foo</h1>` + "\n" +
+				`<h2 id="bar"><a class="anchor" href="#bar">#</a> <a href="http://example.com">Bar</a></h2>` + "\n",
+		},
+		{
+			desc: "attributer and unsafe",
+			give: []ContextOption{
+				WithAttributer(Attributes{}),
+				WithTexter(Text("<b>#</b>")),
+				WithUnsafe(true),
+			},
+			want: `<h1 id="foo">Foo <a href="#foo"><b>#</b></a></h1>` + "\n" +
+				`<h2 id="bar"><a href="http://example.com">Bar</a> <a href="#bar"><b>#</b></a></h2>` + "\n",
+		},
+		{
+			desc: "link policy and levels",
+			give: []ContextOption{
+				WithLinkPolicy(LinkUnwrap),
+				WithLevels(2),
+			},
+			want: `<h1 id="foo">Foo</h1>` + "\n" +
+				`<h2 id="bar">Bar <a class="anchor" href="#bar">¶</a></h2>` + "\n",
+		},
+		{
+			desc: "id prefix and level offset",
+			give: []ContextOption{
+				WithIDPrefix("doc-"),
+				WithLevelOffset(1),
+				WithLinkPolicy(LinkSkip),
+			},
+			want: `<h2 id="doc-foo">Foo <a class="anchor" href="#doc-foo">¶</a></h2>` + "\n" +
+				`<h3 id="doc-bar"><a href="http://example.com">Bar</a></h3>` + "\n",
+		},
+		{
+			desc: "sanitizer",
+			give: []ContextOption{
+				WithTexter(Text("<b>#</b><i>!</i>")),
+				WithSanitizer(&AllowlistSanitizer{Elements: map[string][]string{"b": nil}}),
+				WithLevels(1),
+			},
+			want: `<h1 id="foo">Foo <a class="anchor" href="#foo"><b>#</b>!</a></h1>` + "\n" +
+				`<h2 id="bar"><a href="http://example.com">Bar</a></h2>` + "\n",
+		},
+		{
+			desc: "spacing, accessibility, and no wrap",
+			give: []ContextOption{
+				WithSpacing(&Spacing{NoSeparator: true}),
+				WithAccessibility(&Accessibility{}),
+				WithNoWrap(true),
+				WithLevels(1),
+			},
+			want: `<h1 id="foo"><span style="white-space: nowrap">Foo<a class="anchor" href="#foo" aria-label="Permalink to Foo"><span aria-hidden="true">¶</span></a></span></h1>` + "\n" +
+				`<h2 id="bar"><a href="http://example.com">Bar</a></h2>` + "\n",
+		},
+		{
+			desc: "stats",
+			give: []ContextOption{
+				WithStats(&Stats{ReadingTimeAttribute: true}),
+				WithLevels(1),
+			},
+			want: `<h1 id="foo">Foo <a class="anchor" data-reading-time="1" href="#foo">¶</a></h1>` + "\n" +
+				`<h2 id="bar"><a href="http://example.com">Bar</a></h2>` + "\n",
+		},
+	}
+
+	// All conversions share the same Markdown object.
+	md := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithAttribute(),
+		),
+		goldmark.WithExtensions(&Extender{}),
+	)
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := parser.NewContext()
+			WithContextOptions(ctx, tt.give...)
+
+			var buf bytes.Buffer
+			require.NoError(t, md.Convert([]byte(src), &buf, parser.WithContext(ctx)))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestWithContextOptions_combine(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithExtensions(&Extender{
+			Metadata: func(parser.Context) map[string]any {
+				return map[string]any{"anchor_text": "$"}
+			},
+		}),
+	)
+
+	ctx := parser.NewContext()
+	WithContextOptions(ctx, WithTexter(Text("#")), WithPosition(Before))
+	WithContextOptions(ctx, WithPosition(After))
+
+	var buf bytes.Buffer
+	require.NoError(t, md.Convert([]byte("# Foo\n"), &buf, parser.WithContext(ctx)))
+
+	// Metadata takes precedence over the Texter from the context.
+	assert.Equal(t,
+		`<h1 id="foo">Foo <a class="anchor" href="#foo">$</a></h1>`+"\n",
+		buf.String())
+}
+
+func TestWithContextOptions_concurrent(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithExtensions(&Extender{}),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			text := fmt.Sprint(i)
+			pos := Position(i % 2)
+
+			ctx := parser.NewContext()
+			WithContextOptions(ctx, WithTexter(Text(text)), WithPosition(pos))
+
+			var buf bytes.Buffer
+			if !assert.NoError(t, md.Convert([]byte("# Foo\n"), &buf, parser.WithContext(ctx))) {
+				return
+			}
+
+			anchor := `<a class="anchor" href="#foo">` + text + `</a>`
+			want := `<h1 id="foo">Foo ` + anchor + `</h1>` + "\n"
+			if pos == Before {
+				want = `<h1 id="foo">` + anchor + ` Foo</h1>` + "\n"
+			}
+			assert.Equal(t, want, buf.String())
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWithContextOptions_transformer(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithExtensions(&Extender{
+			Texter: texterFunc(func(h *HeaderInfo) string {
+				return string(h.Text())
+			}),
+			IDPrefix:             "doc-",
+			RewriteFragmentLinks: true,
+			Emoji:                EmojiStrip,
+		}),
+	)
+
+	ctx := parser.NewContext()
+	WithContextOptions(ctx,
+		WithRewriteFragmentLinks(false),
+		WithEmoji(EmojiShortcode),
+		WithEmojiName(func(string) string { return "rocket" }),
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, md.Convert([]byte("# Go 🚀\n\n[x](#go-)\n"), &buf, parser.WithContext(ctx)))
+	assert.Equal(t,
+		`<h1 id="doc-go-">Go 🚀 <a class="anchor" href="#doc-go-">Go rocket</a></h1>`+"\n"+
+			`<p><a href="#go-">x</a></p>`+"\n",
+		buf.String())
+}
+
+func TestWithContextOptions_renderer(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithExtensions(&Extender{Placement: Wrapped}),
+	)
+
+	t.Run("wrapper class", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := parser.NewContext()
+		WithContextOptions(ctx, WithWrapperClass("tenant"))
+
+		var buf bytes.Buffer
+		require.NoError(t, md.Convert([]byte("# Foo\n"), &buf, parser.WithContext(ctx)))
+		assert.Equal(t,
+			`<div class="tenant"><h1 id="foo">Foo</h1><a class="anchor" href="#foo">¶</a></div>`+"\n",
+			buf.String())
+	})
+
+	t.Run("attribute policy", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := parser.NewContext()
+		WithContextOptions(ctx, WithAttributePolicy(&AttributePolicy{Deny: []string{"class"}}))
+
+		var buf bytes.Buffer
+		err := md.Convert([]byte("# Foo\n"), &buf, parser.WithContext(ctx))
+		assert.ErrorIs(t, err, ErrAttributeNotAllowed)
+	})
+}
@@ -0,0 +1,31 @@
+package anchor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlacement_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give Placement
+		want string
+	}{
+		{desc: "inside", give: Inside, want: "Inside"},
+		{desc: "outside", give: Outside, want: "Outside"},
+		{desc: "wrapped", give: Wrapped, want: "Wrapped"},
+		{desc: "unknown", give: 42, want: "Placement(42)"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.give.String())
+		})
+	}
+}
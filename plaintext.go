@@ -0,0 +1,270 @@
+package anchor
+
+import (
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// EmojiPolicy specifies how [PlainText] handles emoji in headers.
+//
+// This covers both emoji written directly in the text (e.g. "🚀")
+// and emoji nodes from goldmark-emoji (e.g. ":rocket:").
+type EmojiPolicy int
+
+//go:generate stringer -type EmojiPolicy
+
+const (
+	// EmojiKeep keeps emoji as-is.
+	// Emoji nodes from goldmark-emoji become their Unicode character.
+	//
+	// This is the default.
+	EmojiKeep EmojiPolicy = iota
+
+	// EmojiStrip drops emoji from the text.
+	EmojiStrip
+
+	// EmojiShortcode replaces emoji with their shortcode name,
+	// for example, "rocket" for "🚀".
+	//
+	// Emoji nodes from goldmark-emoji use their own shortcode.
+	// Emoji written directly in the text are named with
+	// the EmojiName function of [PlainText],
+	// and dropped if it's unset.
+	EmojiShortcode
+)
+
+// PlainText extracts the plain text of a header,
+// without any Markdown markup.
+//
+//	## 🚀 Deploying **fast**
+//
+// The plain text of the header above is "🚀 Deploying fast".
+type PlainText struct {
+	// Emoji specifies how emoji in the header are handled.
+	//
+	// Defaults to EmojiKeep.
+	Emoji EmojiPolicy
+
+	// EmojiName returns the shortcode name of an emoji
+	// written directly in the text, e.g. "rocket" for "🚀".
+	// It's used only if Emoji is EmojiShortcode.
+	// Emoji for which it returns an empty string are dropped.
+	//
+	// Use [go.abhg.dev/goldmark/anchor/emojiname.Name] to name emoji
+	// after their Unicode character names.
+	//
+	// If unset, such emoji are dropped.
+	EmojiName func(emoji string) string
+}
+
+// Text returns the plain text of the given header.
+//
+// The text of emphasis, code spans, links, and image descriptions
+// is included, and the markup around it is dropped.
+// Raw HTML and anchor nodes are dropped entirely.
+// Runs of whitespace are collapsed into a single space.
+func (p *PlainText) Text(h ast.Node, src []byte) []byte {
+	return p.text(h, src, p.Emoji)
+}
+
+// SlugSource returns text of the given header
+// suitable for generating an identifier with a [Slugger].
+//
+// It's the same as Text, except that emoji are dropped
+// unless Emoji is EmojiShortcode
+// because they are not useful in identifiers.
+func (p *PlainText) SlugSource(h ast.Node, src []byte) []byte {
+	emoji := p.Emoji
+	if emoji == EmojiKeep {
+		emoji = EmojiStrip
+	}
+	return p.text(h, src, emoji)
+}
+
+func (p *PlainText) text(h ast.Node, src []byte, emoji EmojiPolicy) []byte {
+	w := plainTextWriter{emoji: emoji, emojiName: p.EmojiName}
+	w.WriteNode(h, src)
+	return w.Bytes()
+}
+
+// plainTextWriter builds plain text,
+// collapsing whitespace and handling emoji according to a policy.
+type plainTextWriter struct {
+	emoji     EmojiPolicy
+	emojiName func(string) string // optional
+	buf       []byte
+	space     bool // whether a space is pending
+}
+
+// Bytes returns the text written so far.
+// Leading and trailing whitespace is dropped.
+func (w *plainTextWriter) Bytes() []byte {
+	return w.buf
+}
+
+// WriteNode writes the text of the given node and its descendants.
+func (w *plainTextWriter) WriteNode(n ast.Node, src []byte) {
+	_ = ast.Walk(n, func(n ast.Node, enter bool) (ast.WalkStatus, error) {
+		if !enter {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := n.(type) {
+		case *Node, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			w.WriteText(n.Value(src))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				w.WriteSpace()
+			}
+		case *ast.String:
+			w.WriteText(n.Value)
+		case *codeSpan:
+			w.WriteText(n.Value)
+		case *ast.AutoLink:
+			w.WriteText(n.Label(src))
+		default:
+			if value, name, ok := emojiNode(n); ok {
+				w.writeEmoji(value, name)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	// Walk never fails because the walker never returns an error.
+}
+
+// WriteSpace records a word boundary.
+func (w *plainTextWriter) WriteSpace() {
+	w.space = true
+}
+
+// WriteText writes text, handling any emoji inside it.
+func (w *plainTextWriter) WriteText(b []byte) {
+	for len(b) > 0 {
+		if w.emoji != EmojiKeep {
+			if size := matchEmoji(b); size > 0 {
+				var name string
+				if w.emoji == EmojiShortcode && w.emojiName != nil {
+					name = w.emojiName(string(b[:size]))
+				}
+				w.writeEmoji(b[:size], name)
+				b = b[size:]
+				continue
+			}
+		}
+
+		r, size := utf8.DecodeRune(b)
+		if unicode.IsSpace(r) {
+			w.WriteSpace()
+		} else {
+			w.writeWord(b[:size])
+		}
+		b = b[size:]
+	}
+}
+
+// _emojiKind is the name of the NodeKind of goldmark-emoji's nodes.
+const _emojiKind = "Emoji"
+
+// emojiNode reports the value and short name of an emoji node
+// from goldmark-emoji.
+//
+// The node is inspected with reflection
+// so that goldmark-emoji is not a dependency of this package.
+// The tests in internal/emojitest fail if this stops matching
+// goldmark-emoji's nodes.
+// It has the following shape:
+//
+//	type Emoji struct {
+//		ast.BaseInline
+//		ShortName []byte
+//		Value     *definition.Emoji // has field Unicode []rune
+//	}
+func emojiNode(n ast.Node) (value []byte, name string, ok bool) {
+	if n.Kind().String() != _emojiKind {
+		return nil, "", false
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(n))
+	shortName, ok := structField[[]byte](v, "ShortName")
+	if !ok {
+		return nil, "", false
+	}
+
+	var unicode []rune
+	if def := structFieldValue(v, "Value"); def.Kind() == reflect.Pointer && !def.IsNil() {
+		unicode, _ = structField[[]rune](def.Elem(), "Unicode")
+	}
+
+	// goldmark-emoji uses U+FFFD for emoji
+	// without a Unicode representation.
+	// These are best represented by their name.
+	if len(unicode) == 0 || (len(unicode) == 1 && unicode[0] == utf8.RuneError) {
+		return shortName, string(shortName), true
+	}
+	return []byte(string(unicode)), string(shortName), true
+}
+
+// structFieldValue returns the exported field of the given struct
+// with the given name, or the zero Value if there isn't one.
+func structFieldValue(v reflect.Value, name string) reflect.Value {
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	f, ok := v.Type().FieldByName(name)
+	if !ok || !f.IsExported() {
+		return reflect.Value{}
+	}
+	// FieldByIndexErr fails instead of panicking
+	// if the field is promoted through a nil embedded pointer.
+	fv, err := v.FieldByIndexErr(f.Index)
+	if err != nil {
+		return reflect.Value{}
+	}
+	return fv
+}
+
+// structField returns the value of the exported field of the given struct
+// with the given name if it has type T.
+func structField[T any](v reflect.Value, name string) (T, bool) {
+	f := structFieldValue(v, name)
+	if !f.IsValid() {
+		var zero T
+		return zero, false
+	}
+	t, ok := f.Interface().(T)
+	return t, ok
+}
+
+func (w *plainTextWriter) writeEmoji(value []byte, name string) {
+	switch w.emoji {
+	case EmojiKeep:
+		w.writeWord(value)
+	case EmojiStrip:
+		// Emoji separate words like whitespace.
+		w.WriteSpace()
+	case EmojiShortcode:
+		w.WriteSpace()
+		if len(name) > 0 {
+			w.writeWord([]byte(name))
+			w.WriteSpace()
+		}
+	}
+}
+
+func (w *plainTextWriter) writeWord(b []byte) {
+	if w.space && len(w.buf) > 0 {
+		w.buf = append(w.buf, ' ')
+	}
+	w.space = false
+	w.buf = append(w.buf, b...)
+}
+
+// headingText returns the plain text contents of a heading,
+// ignoring any anchor nodes inside it.
+func headingText(h ast.Node, src []byte) []byte {
+	return new(PlainText).Text(h, src)
+}
@@ -0,0 +1,214 @@
+package anchor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"go.abhg.dev/goldmark/anchor/emojiname"
+)
+
+func TestPlainText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc      string
+		give      string
+		emoji     EmojiPolicy
+		emojiName func(string) string
+
+		wantText string
+		wantSlug string
+	}{
+		{
+			desc:     "plain",
+			give:     "# Foo bar",
+			wantText: "Foo bar",
+			wantSlug: "Foo bar",
+		},
+		{
+			desc:     "markup",
+			give:     "# Deploying **fast** with `go` and [links](http://example.com)",
+			wantText: "Deploying fast with go and links",
+			wantSlug: "Deploying fast with go and links",
+		},
+		{
+			desc:     "whitespace",
+			give:     "# Foo  *bar* \t baz",
+			wantText: "Foo bar baz",
+			wantSlug: "Foo bar baz",
+		},
+		{
+			desc:     "unicode emoji/keep",
+			give:     "# 🚀 Deploying **fast**",
+			wantText: "🚀 Deploying fast",
+			wantSlug: "Deploying fast",
+		},
+		{
+			desc:     "unicode emoji/strip",
+			give:     "# 🚀 Deploying **fast** 👨‍💻",
+			emoji:    EmojiStrip,
+			wantText: "Deploying fast",
+			wantSlug: "Deploying fast",
+		},
+		{
+			desc:     "unicode emoji/strip between words",
+			give:     "# Foo🚀Bar",
+			emoji:    EmojiStrip,
+			wantText: "Foo Bar",
+			wantSlug: "Foo Bar",
+		},
+		{
+			desc:      "unicode emoji/shortcode",
+			give:      "# 🚀 Deploying **fast**",
+			emoji:     EmojiShortcode,
+			emojiName: emojiname.Name,
+			wantText:  "rocket Deploying fast",
+			wantSlug:  "rocket Deploying fast",
+		},
+		{
+			desc:     "unicode emoji/shortcode without names",
+			give:     "# 🚀 Deploying **fast**",
+			emoji:    EmojiShortcode,
+			wantText: "Deploying fast",
+			wantSlug: "Deploying fast",
+		},
+		{
+			desc:  "unicode emoji/shortcode unnamed",
+			give:  "# 🚀 Deploying 👨‍💻 **fast**",
+			emoji: EmojiShortcode,
+			emojiName: func(emoji string) string {
+				if emoji == "🚀" {
+					return "rocket"
+				}
+				return ""
+			},
+			wantText: "rocket Deploying fast",
+			wantSlug: "rocket Deploying fast",
+		},
+		{
+			desc:      "text symbols",
+			give:      "# ✓ Done ☆ ⌛ x 🄰 ♥ → ©",
+			emoji:     EmojiShortcode,
+			emojiName: emojiname.Name,
+			wantText:  "✓ Done ☆ hourglass x 🄰 ♥ → ©",
+			wantSlug:  "✓ Done ☆ hourglass x 🄰 ♥ → ©",
+		},
+		{
+			desc:     "text symbols/keep",
+			give:     "# ✓ Done ☆ ⌛ x 🄰 ♥ → ©",
+			wantText: "✓ Done ☆ ⌛ x 🄰 ♥ → ©",
+			wantSlug: "✓ Done ☆ x 🄰 ♥ → ©",
+		},
+		{
+			desc:     "emoji node/keep",
+			give:     "# :rocket: Deploying",
+			wantText: "🚀 Deploying",
+			wantSlug: "Deploying",
+		},
+		{
+			desc:     "emoji node/strip",
+			give:     "# Deploying :rocket:",
+			emoji:    EmojiStrip,
+			wantText: "Deploying",
+			wantSlug: "Deploying",
+		},
+		{
+			desc:     "emoji node/shortcode",
+			give:     "# Deploying:rocket:fast",
+			emoji:    EmojiShortcode,
+			wantText: "Deploying rocket fast",
+			wantSlug: "Deploying rocket fast",
+		},
+		{
+			desc:     "emoji node/no unicode",
+			give:     "# :octocat: Repos",
+			wantText: "octocat Repos",
+			wantSlug: "Repos",
+		},
+		{
+			desc:     "emoji only",
+			give:     "# 🚀",
+			emoji:    EmojiStrip,
+			wantText: "",
+			wantSlug: "",
+		},
+	}
+
+	md := goldmark.New(goldmark.WithParserOptions(
+		parser.WithInlineParsers(util.Prioritized(emojiParser{
+			"rocket": {'🚀'},
+			// Emoji without a Unicode representation.
+			"octocat": {0xfffd},
+		}, 999)),
+	))
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			src := []byte(tt.give + "\n")
+			doc := md.Parser().Parse(text.NewReader(src))
+			h, ok := doc.FirstChild().(*ast.Heading)
+			require.True(t, ok, "expected heading, got %T", doc.FirstChild())
+
+			p := PlainText{Emoji: tt.emoji, EmojiName: tt.emojiName}
+			assert.Equal(t, tt.wantText, string(p.Text(h, src)), "text")
+			assert.Equal(t, tt.wantSlug, string(p.SlugSource(h, src)), "slug source")
+		})
+	}
+}
+
+// testEmoji has the same shape as the Emoji node of goldmark-emoji.
+type testEmoji struct {
+	ast.BaseInline
+
+	ShortName []byte
+	Value     *testEmojiDefinition
+}
+
+// testEmojiDefinition has the same shape as goldmark-emoji's definition.Emoji.
+type testEmojiDefinition struct {
+	Name       string
+	ShortNames []string
+	Unicode    []rune
+}
+
+var _kindTestEmoji = ast.NewNodeKind("Emoji")
+
+func (*testEmoji) Kind() ast.NodeKind { return _kindTestEmoji }
+
+func (n *testEmoji) Dump(src []byte, level int) {
+	ast.DumpHelper(n, src, level, nil, nil)
+}
+
+// emojiParser parses ":name:" into emojiNodes
+// like goldmark-emoji does.
+type emojiParser map[string][]rune
+
+func (emojiParser) Trigger() []byte { return []byte{':'} }
+
+func (p emojiParser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	end := bytes.IndexByte(line[1:], ':')
+	if end < 0 {
+		return nil
+	}
+	name := line[1 : end+1]
+	unicode, ok := p[string(name)]
+	if !ok {
+		return nil
+	}
+	block.Advance(end + 2)
+	return &testEmoji{
+		ShortName: name,
+		Value:     &testEmojiDefinition{ShortNames: []string{string(name)}, Unicode: unicode},
+	}
+}
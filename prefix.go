@@ -0,0 +1,72 @@
+package anchor
+
+import (
+	"net/url"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// prefixIDs adds a prefix to the IDs of all headers in the document.
+// It returns the set of IDs as they were before the prefix was added.
+func prefixIDs(doc ast.Node, prefix []byte) map[string]struct{} {
+	ids := make(map[string]struct{})
+	_ = ast.Walk(doc, func(n ast.Node, enter bool) (ast.WalkStatus, error) {
+		if !enter {
+			return ast.WalkContinue, nil
+		}
+		h, ok := n.(*ast.Heading)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+
+		if idattr, ok := h.AttributeString("id"); ok {
+			if id, ok := idattr.([]byte); ok {
+				ids[string(id)] = struct{}{}
+
+				newID := make([]byte, 0, len(prefix)+len(id))
+				newID = append(newID, prefix...)
+				newID = append(newID, id...)
+				h.SetAttributeString("id", newID)
+			}
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	// Walk never fails because the walker never returns an error.
+
+	return ids
+}
+
+// rewriteFragmentLinks adds a prefix to links in the document
+// that point to one of the given IDs, e.g. "#foo".
+func rewriteFragmentLinks(doc ast.Node, prefix []byte, ids map[string]struct{}) {
+	_ = ast.Walk(doc, func(n ast.Node, enter bool) (ast.WalkStatus, error) {
+		if !enter {
+			return ast.WalkContinue, nil
+		}
+		l, ok := n.(*ast.Link)
+		if !ok || len(l.Destination) == 0 || l.Destination[0] != '#' {
+			return ast.WalkContinue, nil
+		}
+
+		frag := l.Destination[1:]
+		if _, ok := ids[string(frag)]; !ok {
+			// The link may use a percent-encoded form of the ID.
+			decoded, err := url.PathUnescape(string(frag))
+			if err != nil {
+				return ast.WalkContinue, nil
+			}
+			if _, ok := ids[decoded]; !ok {
+				return ast.WalkContinue, nil
+			}
+		}
+
+		prefix := escapeFragment(prefix)
+		dest := make([]byte, 0, 1+len(prefix)+len(frag))
+		dest = append(dest, '#')
+		dest = append(dest, prefix...)
+		dest = append(dest, frag...)
+		l.Destination = dest
+		return ast.WalkContinue, nil
+	})
+	// Walk never fails because the walker never returns an error.
+}
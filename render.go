@@ -7,14 +7,99 @@ import (
 	"github.com/yuin/goldmark/util"
 )
 
+// Placement specifies whether an anchor is rendered
+// inside or outside the heading element.
+type Placement int
+
+//go:generate stringer -type Placement
+
+const (
+	// Inside renders the anchor inside the heading element,
+	// next to the heading text.
+	//
+	//	<h1 id="foo">Foo <a href="#foo">¶</a></h1>
+	//
+	// This is the default.
+	Inside Placement = iota
+
+	// Outside renders the anchor as a sibling of the heading element.
+	//
+	//	<h1 id="foo">Foo</h1><a href="#foo">¶</a>
+	//
+	// Position determines whether the anchor is placed
+	// before or after the heading element.
+	Outside
+
+	// Wrapped renders the anchor as a sibling of the heading element,
+	// and wraps both inside a <div>.
+	//
+	//	<div class="heading-wrapper"><h1 id="foo">Foo</h1><a href="#foo">¶</a></div>
+	//
+	// Position determines whether the anchor is placed
+	// before or after the heading element.
+	Wrapped
+)
+
+const _defaultWrapperClass = "heading-wrapper"
+
 // Renderer renders anchor [Node]s.
 type Renderer struct {
 	// Position specifies where in the header text
 	// the anchor is being added.
+	//
+	// Deprecated: Renderer places each anchor
+	// according to the Position field of the [Node],
+	// which is set by the [Transformer].
+	// This field has no effect.
 	Position Position
+
 	// Unsafe specifies whether the Texter values will be HTML escaped or
 	// not.
 	Unsafe bool
+
+	// Sanitizer cleans up HTML in anchor text before it's written.
+	// Use this to allow limited markup in anchor text,
+	// for example, with [AllowlistSanitizer].
+	//
+	// This has no effect if Unsafe is set.
+	// If unset, anchor text is HTML escaped.
+	Sanitizer Sanitizer
+
+	// Placement specifies whether the anchor is rendered
+	// inside or outside the heading element.
+	//
+	// If this is Outside or Wrapped,
+	// Renderer takes over rendering of headings.
+	//
+	// Defaults to Inside.
+	Placement Placement
+
+	// WrapperClass is the class of the <div> that wraps headings
+	// if Placement is Wrapped.
+	//
+	// Defaults to "heading-wrapper".
+	WrapperClass string
+
+	// Spacing configures the whitespace between the heading text
+	// and anchors rendered inside the heading.
+	// It has no effect if Placement is not Inside.
+	//
+	// Defaults to a single space.
+	Spacing *Spacing
+
+	// Accessibility configures accessible rendering of anchors.
+	//
+	// If unset, anchors are rendered without additional markup
+	// for assistive technologies.
+	Accessibility *Accessibility
+
+	// AttributePolicy specifies which attributes anchors may have.
+	// Rendering fails with an [*AttributeError]
+	// if an anchor has an attribute that isn't allowed.
+	//
+	// If unset, unsafe attributes like event handlers are rejected,
+	// and all other attributes are allowed.
+	AttributePolicy *AttributePolicy
 }
 
 var _ renderer.NodeRenderer = (*Renderer)(nil)
@@ -22,42 +107,264 @@ var _ renderer.NodeRenderer = (*Renderer)(nil)
 // RegisterFuncs registers functions against the provided goldmark Registerer.
 func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
 	reg.Register(Kind, r.RenderNode)
+	reg.Register(_kindNoWrap, r.renderNoWrap)
+	reg.Register(_kindCodeSpan, r.renderCodeSpan)
+	if r.Placement != Inside {
+		reg.Register(ast.KindHeading, r.RenderHeading)
+	}
 }
 
 // RenderNode renders an anchor node.
 // Goldmark will invoke this method when it encounters a Node.
-func (r *Renderer) RenderNode(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
-	// If position is Before, we need to add the anchor when entering;
-	// otherwise when exiting.
-	if (r.Position == Before) != entering {
-		return ast.WalkContinue, nil
+//
+// If the Node has children, they're rendered as the anchor text
+// instead of Node.Value.
+func (r *Renderer) RenderNode(w util.BufWriter, src []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*Node)
+	r = r.forNode(n)
+	if r.Placement != Inside {
+		return r.renderOutside(w, src, n, entering)
 	}
 
-	n := node.(*Node)
 	if len(n.ID) == 0 {
+		return ast.WalkSkipChildren, nil
+	}
+
+	// Add leading/trailing separator depending on position.
+	sep := r.Spacing.separator()
+	if !entering {
+		r.closeAnchor(w, src, n)
+		if n.Position == Before {
+			_, _ = w.Write(sep)
+		}
 		return ast.WalkContinue, nil
 	}
 
-	// Add leading/trailing ' ' depending on position.
-	if r.Position == Before {
-		defer func() {
-			_ = w.WriteByte(' ')
-		}()
+	if err := r.AttributePolicy.validateAttributes(n); err != nil {
+		return ast.WalkStop, err
+	}
+	if n.Position != Before {
+		_, _ = w.Write(sep)
+	}
+	r.openAnchor(w, src, n)
+	if !n.HasChildren() {
+		r.writeValue(w, n)
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderOutside renders an anchor node placed outside the heading element.
+//
+// Anchors are usually rendered entirely by RenderHeading.
+// If the anchor has children that must be rendered by goldmark,
+// RenderHeading leaves the start or end of the heading element
+// to this function instead.
+func (r *Renderer) renderOutside(w util.BufWriter, src []byte, n *Node, entering bool) (ast.WalkStatus, error) {
+	h, ok := n.Parent().(*ast.Heading)
+	if !ok || !splitAnchor(h, n) {
+		return ast.WalkSkipChildren, nil
+	}
+
+	if entering {
+		if n.Position == After {
+			closeHeading(w, h)
+		}
+		r.openAnchor(w, src, n)
+	} else {
+		r.closeAnchor(w, src, n)
+		if n.Position == Before {
+			openHeading(w, h)
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+// splitAnchor reports whether an anchor placed outside the given heading
+// has children that must be rendered as part of the heading's children.
+//
+// This requires the anchor to be the first child of the heading
+// if it's positioned before the heading, and the last child otherwise.
+func splitAnchor(h *ast.Heading, n *Node) bool {
+	if n == nil || len(n.ID) == 0 || !n.HasChildren() {
+		return false
+	}
+	if n.Position == Before {
+		return h.FirstChild() == n
+	}
+	return h.LastChild() == n
+}
+
+// RenderHeading renders a heading and its anchor
+// with the anchor placed outside the heading element.
+// Goldmark will invoke this method when it encounters an [ast.Heading]
+// if Placement is Outside or Wrapped.
+func (r *Renderer) RenderHeading(w util.BufWriter, src []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	h := node.(*ast.Heading)
+	n := headingAnchor(h)
+	if n != nil && len(n.ID) == 0 {
+		n = nil
+	}
+	if r.Placement == Inside {
+		// The anchor is rendered by RenderNode
+		// as part of the heading's children.
+		n = nil
+	}
+	r = r.forNode(n)
+
+	if entering && n != nil {
+		if err := r.AttributePolicy.validateAttributes(n); err != nil {
+			return ast.WalkStop, err
+		}
+	}
+
+	// If the anchor must be rendered by goldmark,
+	// renderOutside writes the anchor and the heading tag next to it.
+	split := splitAnchor(h, n)
+	wrap := n != nil && r.Placement == Wrapped
+	if entering {
+		if wrap {
+			_, _ = w.WriteString(`<div class="`)
+			_, _ = w.Write(util.EscapeHTML([]byte(r.wrapperClass())))
+			_, _ = w.WriteString(`">`)
+		}
+		if n != nil && n.Position == Before {
+			if split {
+				return ast.WalkContinue, nil
+			}
+			r.renderAnchor(w, src, n)
+		}
+		openHeading(w, h)
+	} else {
+		if !split || n.Position != After {
+			closeHeading(w, h)
+		}
+		if n != nil && n.Position == After && !split {
+			r.renderAnchor(w, src, n)
+		}
+		if wrap {
+			_, _ = w.WriteString("</div>")
+		}
+		_ = w.WriteByte('\n')
+	}
+
+	return ast.WalkContinue, nil
+}
+
+func openHeading(w util.BufWriter, h *ast.Heading) {
+	_, _ = w.WriteString("<h")
+	_ = w.WriteByte("0123456"[h.Level])
+	if h.Attributes() != nil {
+		html.RenderAttributes(w, h, html.HeadingAttributeFilter)
+	}
+	_ = w.WriteByte('>')
+}
+
+func closeHeading(w util.BufWriter, h *ast.Heading) {
+	_, _ = w.WriteString("</h")
+	_ = w.WriteByte("0123456"[h.Level])
+	_ = w.WriteByte('>')
+}
+
+func (r *Renderer) renderNoWrap(w util.BufWriter, _ []byte, _ ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<span style="white-space: nowrap">`)
 	} else {
-		_ = w.WriteByte(' ')
+		_, _ = w.WriteString("</span>")
+	}
+	return ast.WalkContinue, nil
+}
+
+// forNode returns the Renderer to use for the given anchor node,
+// with the settings overridden for its conversion applied.
+func (r *Renderer) forNode(n *Node) *Renderer {
+	if n == nil || n.render == nil {
+		return r
+	}
+	nr := *r
+	n.render.apply(&nr)
+	return &nr
+}
+
+func (r *Renderer) wrapperClass() string {
+	if len(r.WrapperClass) == 0 {
+		return _defaultWrapperClass
+	}
+	return r.WrapperClass
+}
+
+// renderAnchor renders an anchor with its Value as the text.
+func (r *Renderer) renderAnchor(w util.BufWriter, src []byte, n *Node) {
+	r.openAnchor(w, src, n)
+	r.writeValue(w, n)
+	r.closeAnchor(w, src, n)
+}
+
+// openAnchor writes the start of an anchor, up to its text.
+func (r *Renderer) openAnchor(w util.BufWriter, src []byte, n *Node) {
+	a11y := r.Accessibility
+	var label []byte
+	if a11y != nil && !a11y.HiddenText {
+		label = r.label(src, n)
 	}
 
 	_, _ = w.WriteString("<a")
-	html.RenderAttributes(w, node, nil)
-	_, _ = w.WriteString(` href="#`)
-	_, _ = w.Write(util.EscapeHTML(n.ID))
-	_, _ = w.WriteString(`">`)
-	if r.Unsafe {
+	html.RenderAttributes(w, n, nil)
+	_, _ = w.WriteString(` href="`)
+	_, _ = w.Write(util.EscapeHTML(n.href()))
+	_ = w.WriteByte('"')
+	if label != nil {
+		// Don't override a label set by the Attributer.
+		if _, ok := n.AttributeString("aria-label"); !ok {
+			_, _ = w.WriteString(` aria-label="`)
+			_, _ = w.Write(label)
+			_ = w.WriteByte('"')
+		}
+	}
+	_ = w.WriteByte('>')
+
+	if a11y != nil {
+		_, _ = w.WriteString(`<span aria-hidden="true">`)
+	}
+}
+
+// writeValue writes the Value of an anchor as its text.
+func (r *Renderer) writeValue(w util.BufWriter, n *Node) {
+	switch {
+	case r.Unsafe:
 		_, _ = w.Write(n.Value)
-	} else {
+	case r.Sanitizer != nil:
+		_, _ = w.Write(r.Sanitizer.Sanitize(n.Value))
+	default:
 		_, _ = w.Write(util.EscapeHTML(n.Value))
 	}
+}
+
+// closeAnchor writes the end of an anchor started by openAnchor.
+func (r *Renderer) closeAnchor(w util.BufWriter, src []byte, n *Node) {
+	if a11y := r.Accessibility; a11y != nil {
+		_, _ = w.WriteString("</span>")
+		if a11y.HiddenText {
+			if label := r.label(src, n); label != nil {
+				_, _ = w.WriteString(`<span class="`)
+				_, _ = w.Write(util.EscapeHTML([]byte(a11y.hiddenTextClass())))
+				_, _ = w.WriteString(`">`)
+				_, _ = w.Write(label)
+				_, _ = w.WriteString("</span>")
+			}
+		}
+	}
 	_, _ = w.WriteString("</a>")
+}
 
-	return ast.WalkContinue, nil
+// label returns the HTML-escaped accessible name for the given anchor,
+// or nil if it doesn't have one.
+func (r *Renderer) label(src []byte, n *Node) []byte {
+	if r.Accessibility == nil {
+		return nil
+	}
+	h := anchorHeading(n)
+	if h == nil {
+		return nil
+	}
+	return r.Accessibility.label(headingText(h, src))
 }
@@ -6,6 +6,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/util"
 )
@@ -14,12 +16,14 @@ func TestRenderer(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		desc   string
-		give   Node
-		attrs  map[string]string
-		pos    Position
-		want   string
-		unsafe bool
+		desc      string
+		give      Node
+		attrs     map[string]string
+		want      string
+		unsafe    bool
+		sanitizer Sanitizer
+		a11y      *Accessibility
+		spacing   *Spacing
 	}{
 		{desc: "empty ID"},
 		{
@@ -32,10 +36,10 @@ func TestRenderer(t *testing.T) {
 		},
 		{
 			desc: "before",
-			pos:  Before,
 			give: Node{
-				ID:    []byte("hello"),
-				Value: []byte("#"),
+				ID:       []byte("hello"),
+				Value:    []byte("#"),
+				Position: Before,
 			},
 			want: `<a href="#hello">#</a> `,
 		},
@@ -67,6 +71,71 @@ func TestRenderer(t *testing.T) {
 			want:   ` <a foo="bar" href="#hello"><unsafe></unsafe></a>`,
 			unsafe: true,
 		},
+		{
+			desc: "sanitized",
+			give: Node{
+				ID:    []byte("hello"),
+				Value: []byte(`<span class="icon" onclick="x()">#</span><script>x()</script>`),
+			},
+			sanitizer: new(AllowlistSanitizer),
+			want:      ` <a href="#hello"><span class="icon">#</span></a>`,
+		},
+		{
+			desc: "sanitizer/unsafe",
+			give: Node{
+				ID:    []byte("hello"),
+				Value: []byte(`<script>x()</script>`),
+			},
+			unsafe:    true,
+			sanitizer: new(AllowlistSanitizer),
+			want:      ` <a href="#hello"><script>x()</script></a>`,
+		},
+		{
+			desc: "accessible/no heading",
+			give: Node{
+				ID:    []byte("hello"),
+				Value: []byte("#"),
+			},
+			a11y: &Accessibility{HiddenText: true},
+			want: ` <a href="#hello"><span aria-hidden="true">#</span></a>`,
+		},
+		{
+			desc: "separator/after",
+			give: Node{
+				ID:    []byte("hello"),
+				Value: []byte("#"),
+			},
+			spacing: &Spacing{Separator: NoBreakSpace},
+			want:    "\u00a0<a href=\"#hello\">#</a>",
+		},
+		{
+			desc: "separator/before",
+			give: Node{
+				ID:       []byte("hello"),
+				Value:    []byte("#"),
+				Position: Before,
+			},
+			spacing: &Spacing{Separator: ThinSpace},
+			want:    "<a href=\"#hello\">#</a>\u202f",
+		},
+		{
+			desc: "separator/none",
+			give: Node{
+				ID:    []byte("hello"),
+				Value: []byte("#"),
+			},
+			spacing: &Spacing{NoSeparator: true},
+			want:    `<a href="#hello">#</a>`,
+		},
+		{
+			desc: "separator/default",
+			give: Node{
+				ID:    []byte("hello"),
+				Value: []byte("#"),
+			},
+			spacing: &Spacing{},
+			want:    ` <a href="#hello">#</a>`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -75,8 +144,10 @@ func TestRenderer(t *testing.T) {
 			t.Parallel()
 
 			anchorR := Renderer{
-				Position: tt.pos,
-				Unsafe:   tt.unsafe,
+				Unsafe:        tt.unsafe,
+				Sanitizer:     tt.sanitizer,
+				Accessibility: tt.a11y,
+				Spacing:       tt.spacing,
 			}
 			r := renderer.NewRenderer(
 				renderer.WithNodeRenderers(
@@ -96,3 +167,29 @@ func TestRenderer(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderer_positionFromNode(t *testing.T) {
+	t.Parallel()
+
+	// Transformer and Renderer installed separately,
+	// with only the Transformer aware of the position.
+	md := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithASTTransformers(
+				util.Prioritized(&Transformer{Position: Before}, 100),
+			),
+		),
+		goldmark.WithRendererOptions(
+			renderer.WithNodeRenderers(
+				util.Prioritized(&Renderer{}, 100),
+			),
+		),
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, md.Convert([]byte("# Foo\n"), &buf))
+	assert.Equal(t,
+		`<h1 id="foo"><a class="anchor" href="#foo">¶</a> Foo</h1>`+"\n",
+		buf.String())
+}
@@ -0,0 +1,103 @@
+package anchor
+
+import (
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// NopRenderer renders anchor [Node]s as nothing.
+//
+// Install it instead of [Renderer] into goldmark renderers
+// that produce plain text output, where anchors are not useful.
+//
+//	renderer.NewRenderer(
+//		renderer.WithNodeRenderers(
+//			// ...
+//			util.Prioritized(&anchor.NopRenderer{}, 100),
+//		),
+//	)
+type NopRenderer struct{}
+
+var _ renderer.NodeRenderer = (*NopRenderer)(nil)
+
+// RegisterFuncs registers functions against the provided goldmark Registerer.
+func (r *NopRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(Kind, r.RenderNode)
+}
+
+// RenderNode renders nothing for an anchor node.
+func (*NopRenderer) RenderNode(util.BufWriter, []byte, ast.Node, bool) (ast.WalkStatus, error) {
+	return ast.WalkSkipChildren, nil
+}
+
+// TerminalRenderer renders anchor [Node]s as terminal hyperlinks
+// using the OSC 8 escape sequence.
+//
+// Install it instead of [Renderer] into goldmark renderers
+// that produce output for terminals.
+// Terminals that support OSC 8 will show the anchor text
+// as a link to BaseURL with the header ID as the fragment.
+// Other terminals will show only the anchor text.
+type TerminalRenderer struct {
+	// BaseURL is the URL of the rendered document.
+	// The anchor links to this URL with the header ID as the fragment.
+	//
+	// For example, with BaseURL "https://example.com/docs",
+	// the anchor for header "foo" links to "https://example.com/docs#foo".
+	BaseURL string
+}
+
+var _ renderer.NodeRenderer = (*TerminalRenderer)(nil)
+
+// RegisterFuncs registers functions against the provided goldmark Registerer.
+func (r *TerminalRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(Kind, r.RenderNode)
+}
+
+// RenderNode renders an anchor node as a terminal hyperlink.
+// Goldmark will invoke this method when it encounters a Node.
+//
+// Children of the Node are not rendered.
+// Node.Value is always used as the anchor text.
+func (r *TerminalRenderer) RenderNode(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*Node)
+	pos := n.Position
+	if (pos == Before) != entering {
+		return ast.WalkSkipChildren, nil
+	}
+
+	if len(n.ID) == 0 {
+		return ast.WalkSkipChildren, nil
+	}
+
+	if pos == Before {
+		defer func() {
+			_ = w.WriteByte(' ')
+		}()
+	} else {
+		_ = w.WriteByte(' ')
+	}
+
+	// OSC 8 ; params ; URI ST text OSC 8 ; ; ST
+	_, _ = w.WriteString("\x1b]8;;")
+	writeTerminalSafe(w, []byte(r.BaseURL))
+	_ = w.WriteByte('#')
+	writeTerminalSafe(w, escapeFragment(n.ID))
+	_, _ = w.WriteString("\x1b\\")
+	writeTerminalSafe(w, n.Value)
+	_, _ = w.WriteString("\x1b]8;;\x1b\\")
+
+	return ast.WalkSkipChildren, nil
+}
+
+// writeTerminalSafe writes b to w, dropping control characters
+// that could terminate or inject escape sequences.
+func writeTerminalSafe(w util.BufWriter, b []byte) {
+	for _, c := range b {
+		if c < 0x20 || c == 0x7f {
+			continue
+		}
+		_ = w.WriteByte(c)
+	}
+}
@@ -0,0 +1,92 @@
+package anchor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestNopRenderer(t *testing.T) {
+	t.Parallel()
+
+	r := renderer.NewRenderer(
+		renderer.WithNodeRenderers(
+			util.Prioritized(&NopRenderer{}, 100),
+		),
+	)
+
+	node := Node{
+		ID:    []byte("hello"),
+		Value: []byte("#"),
+	}
+	node.AppendChild(&node, ast.NewString([]byte("child")))
+
+	var buff bytes.Buffer
+	require.NoError(t, r.Render(&buff, nil /* src */, &node))
+	assert.Empty(t, buff.String())
+}
+
+func TestTerminalRenderer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc    string
+		give    Node
+		baseURL string
+		want    string
+	}{
+		{desc: "empty ID"},
+		{
+			desc: "after",
+			give: Node{
+				ID:    []byte("hello"),
+				Value: []byte("#"),
+			},
+			baseURL: "https://example.com/docs",
+			want:    " \x1b]8;;https://example.com/docs#hello\x1b\\#\x1b]8;;\x1b\\",
+		},
+		{
+			desc: "before",
+			give: Node{
+				ID:       []byte("hello"),
+				Value:    []byte("#"),
+				Position: Before,
+			},
+			want: "\x1b]8;;#hello\x1b\\#\x1b]8;;\x1b\\ ",
+		},
+		{
+			desc: "control characters",
+			give: Node{
+				ID:    []byte("hel\x1blo"),
+				Value: []byte("#\a"),
+			},
+			baseURL: "https://example.com/\x1b]8;;",
+			want:    " \x1b]8;;https://example.com/]8;;#hel%1Blo\x1b\\#\x1b]8;;\x1b\\",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			r := renderer.NewRenderer(
+				renderer.WithNodeRenderers(
+					util.Prioritized(&TerminalRenderer{
+						BaseURL: tt.baseURL,
+					}, 100),
+				),
+			)
+
+			node := tt.give
+			var buff bytes.Buffer
+			require.NoError(t, r.Render(&buff, nil /* src */, &node))
+			assert.Equal(t, tt.want, buff.String())
+		})
+	}
+}
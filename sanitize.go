@@ -0,0 +1,269 @@
+package anchor
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/yuin/goldmark/util"
+	"golang.org/x/net/html"
+)
+
+// Sanitizer cleans up HTML in anchor text
+// so that it can be written to the output as-is.
+//
+// Set the Sanitizer field of [Renderer] or [Extender]
+// to allow limited markup in anchor text.
+type Sanitizer interface {
+	// Sanitize returns a safe version of the given HTML.
+	//
+	// The returned HTML is written to the output without escaping.
+	Sanitize(value []byte) []byte
+}
+
+// AllowlistSanitizer is a [Sanitizer] that keeps only
+// elements and attributes in an allowlist.
+// Its zero value uses a conservative default allowlist
+// that permits simple formatting, images, and SVG icons.
+//
+//	&anchor.Extender{
+//		Texter:    anchor.Text(`<svg class="icon" viewBox="0 0 16 16"><path d="M4 8h8"/></svg>`),
+//		Sanitizer: &anchor.AllowlistSanitizer{},
+//	}
+//
+// Other elements are dropped, keeping their text.
+// The contents of elements like <script> and <style> are dropped entirely.
+//
+// Attributes rejected by [AttributePolicy] regardless of its settings
+// are always dropped, even if they're allowlisted,
+// so event handlers and 'javascript:' URLs never make it through.
+type AllowlistSanitizer struct {
+	// Elements maps lowercase names of allowed elements
+	// to the names of attributes allowed on them.
+	//
+	// Defaults to DefaultSanitizerElements if unset.
+	Elements map[string][]string
+}
+
+var _ Sanitizer = (*AllowlistSanitizer)(nil)
+
+// _commonSanitizerAttributes are allowed on all elements
+// in the default allowlist.
+var _commonSanitizerAttributes = []string{"class", "title", "aria-hidden", "aria-label", "role"}
+
+// _svgShapeAttributes are presentation attributes allowed on SVG shapes
+// in the default allowlist.
+var _svgShapeAttributes = []string{
+	"fill", "fill-rule", "clip-rule", "stroke", "stroke-width",
+	"stroke-linecap", "stroke-linejoin", "transform", "opacity",
+}
+
+// DefaultSanitizerElements returns the default allowlist
+// used by [AllowlistSanitizer].
+//
+// Modify the returned map to build a custom allowlist.
+func DefaultSanitizerElements() map[string][]string {
+	elements := map[string][]string{
+		"span":   nil,
+		"b":      nil,
+		"i":      nil,
+		"em":     nil,
+		"strong": nil,
+		"code":   nil,
+		"small":  nil,
+		"sub":    nil,
+		"sup":    nil,
+		"img":    {"src", "alt", "width", "height"},
+
+		"svg":      {"xmlns", "viewbox", "width", "height", "fill", "stroke", "focusable"},
+		"g":        _svgShapeAttributes,
+		"path":     append([]string{"d"}, _svgShapeAttributes...),
+		"circle":   append([]string{"cx", "cy", "r"}, _svgShapeAttributes...),
+		"ellipse":  append([]string{"cx", "cy", "rx", "ry"}, _svgShapeAttributes...),
+		"rect":     append([]string{"x", "y", "width", "height", "rx", "ry"}, _svgShapeAttributes...),
+		"line":     append([]string{"x1", "y1", "x2", "y2"}, _svgShapeAttributes...),
+		"polyline": append([]string{"points"}, _svgShapeAttributes...),
+		"polygon":  append([]string{"points"}, _svgShapeAttributes...),
+	}
+	for name, attrs := range elements {
+		elements[name] = append(slices.Clip(attrs), _commonSanitizerAttributes...)
+	}
+	return elements
+}
+
+var _defaultSanitizerElements = DefaultSanitizerElements()
+
+// _droppedContentElements are elements that are always dropped
+// along with their contents.
+var _droppedContentElements = []string{
+	"script", "style", "template", "iframe", "noscript",
+	"noembed", "noframes", "object", "plaintext", "textarea", "title", "xmp",
+}
+
+// _svgAttributeNames maps lowercase SVG attribute names
+// to their case-sensitive spelling.
+var _svgAttributeNames = map[string]string{
+	"viewbox":             "viewBox",
+	"preserveaspectratio": "preserveAspectRatio",
+}
+
+// Sanitize returns the given HTML with elements and attributes
+// that are not in the allowlist removed.
+// All elements in the returned HTML are closed.
+func (s *AllowlistSanitizer) Sanitize(src []byte) []byte {
+	elements := s.Elements
+	if elements == nil {
+		elements = _defaultSanitizerElements
+	}
+
+	w := sanitizeWriter{elements: elements}
+	z := html.NewTokenizer(bytes.NewReader(src))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			// The only error for an in-memory reader is io.EOF.
+			break
+		}
+
+		tok := z.Token()
+		switch tt {
+		case html.TextToken:
+			if w.skip == 0 {
+				w.buf.Write(util.EscapeHTML([]byte(tok.Data)))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			w.StartTag(tok, tt == html.SelfClosingTagToken)
+		case html.EndTagToken:
+			w.EndTag(tok.Data)
+		}
+	}
+
+	for len(w.open) > 0 {
+		w.closeLast()
+	}
+	return w.buf.Bytes()
+}
+
+// sanitizeWriter writes the sanitized version of an HTML token stream.
+type sanitizeWriter struct {
+	elements map[string][]string // allowlist
+
+	buf   bytes.Buffer
+	open  []string // allowed elements that are open
+	skip  int      // depth inside elements whose contents are dropped
+	inSVG int      // number of open <svg> elements
+}
+
+func (w *sanitizeWriter) StartTag(tok html.Token, selfClosing bool) {
+	if slices.Contains(_droppedContentElements, tok.Data) {
+		// The tokenizer treats the contents of most of these elements
+		// as text even if the tag is self-closing.
+		w.skip++
+		return
+	}
+	if w.skip > 0 {
+		return
+	}
+
+	allowed, ok := w.elements[tok.Data]
+	if !ok {
+		return
+	}
+	if tok.Data == "svg" {
+		w.inSVG++
+	}
+
+	w.buf.WriteByte('<')
+	w.buf.WriteString(tok.Data)
+	for i, attr := range tok.Attr {
+		if !allowAttribute(allowed, attr) {
+			continue
+		}
+		// Like browsers, keep only the first of duplicate attributes.
+		if slices.ContainsFunc(tok.Attr[:i], func(a html.Attribute) bool {
+			return a.Key == attr.Key
+		}) {
+			continue
+		}
+
+		name := attr.Key
+		if svgName, ok := _svgAttributeNames[name]; ok && w.inSVG > 0 {
+			name = svgName
+		}
+		w.buf.WriteByte(' ')
+		w.buf.WriteString(name)
+		w.buf.WriteString(`="`)
+		w.buf.Write(util.EscapeHTML([]byte(attr.Val)))
+		w.buf.WriteByte('"')
+	}
+
+	switch {
+	case isVoidElement(tok.Data):
+		w.buf.WriteString(" />")
+	case selfClosing && w.inSVG > 0:
+		// Self-closing tags are only meaningful inside SVG.
+		w.buf.WriteString(" />")
+		if tok.Data == "svg" {
+			w.inSVG--
+		}
+	case selfClosing:
+		w.buf.WriteString("></")
+		w.buf.WriteString(tok.Data)
+		w.buf.WriteByte('>')
+	default:
+		w.buf.WriteByte('>')
+		w.open = append(w.open, tok.Data)
+	}
+}
+
+func (w *sanitizeWriter) EndTag(name string) {
+	if slices.Contains(_droppedContentElements, name) {
+		w.skip = max(w.skip-1, 0)
+		return
+	}
+	if w.skip > 0 {
+		return
+	}
+
+	// Close all elements up to the matching one.
+	if i := slices.Index(w.open, name); i >= 0 {
+		for len(w.open) > i {
+			w.closeLast()
+		}
+	}
+}
+
+// closeLast closes the most recently opened element.
+func (w *sanitizeWriter) closeLast() {
+	name := w.open[len(w.open)-1]
+	w.open = w.open[:len(w.open)-1]
+	if name == "svg" {
+		w.inSVG--
+	}
+
+	w.buf.WriteString("</")
+	w.buf.WriteString(name)
+	w.buf.WriteByte('>')
+}
+
+// allowAttribute reports whether the given attribute
+// is in the allowed list and is safe.
+func allowAttribute(allowed []string, attr html.Attribute) bool {
+	if !slices.Contains(allowed, attr.Key) {
+		return false
+	}
+
+	// Apply the rules that no AttributePolicy can override.
+	var policy *AttributePolicy
+	return policy.validate([]byte(attr.Key), []byte(attr.Val)) == nil
+}
+
+// isVoidElement reports whether the given HTML element
+// never has any contents or an end tag.
+func isVoidElement(name string) bool {
+	switch name {
+	case "area", "base", "br", "col", "embed", "hr", "img", "input",
+		"link", "meta", "source", "track", "wbr":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package anchor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowlistSanitizer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc     string
+		elements map[string][]string
+		give     string
+		want     string
+	}{
+		{desc: "text", give: "¶", want: "¶"},
+		{desc: "escapes text", give: "a &amp; b < c", want: "a &amp; b &lt; c"},
+		{
+			desc: "span",
+			give: `<span class="icon" style="color: red">#</span>`,
+			want: `<span class="icon">#</span>`,
+		},
+		{
+			desc: "svg",
+			give: `<svg viewBox="0 0 16 16" class="icon"><path d="M4 8h8" onclick="x()"/></svg>`,
+			want: `<svg viewBox="0 0 16 16" class="icon"><path d="M4 8h8" /></svg>`,
+		},
+		{
+			desc: "img",
+			give: `<img src="/link.svg" alt="link" onerror="alert(1)">`,
+			want: `<img src="/link.svg" alt="link" />`,
+		},
+		{
+			desc: "img javascript",
+			give: `<img src=" javascript:alert(1)" alt="x">`,
+			want: `<img alt="x" />`,
+		},
+		{
+			desc: "script",
+			give: `<b>a</b><script>alert("<b>x</b>")</script>b`,
+			want: `<b>a</b>b`,
+		},
+		{
+			desc: "nested dropped",
+			give: `<object><b>x</b><script>y</script></object><i>z</i>`,
+			want: `<i>z</i>`,
+		},
+		{
+			desc: "unknown element keeps text",
+			give: `<a href="#x"><u>link</u></a>`,
+			want: `link`,
+		},
+		{desc: "unclosed", give: `<b><i>x`, want: `<b><i>x</i></b>`},
+		{desc: "misnested", give: `<b><i>x</b>y</i>`, want: `<b><i>x</i></b>y`},
+		{desc: "stray end tag", give: `x</span>`, want: `x`},
+		{desc: "self-closing span", give: `<span class="a"/>x`, want: `<span class="a"></span>x`},
+		{
+			desc: "duplicate attributes",
+			give: `<span class="a" class="b">x</span>`,
+			want: `<span class="a">x</span>`,
+		},
+		{desc: "comment", give: `a<!-- <script> -->b`, want: `ab`},
+		{desc: "attribute escaping", give: `<span title='"&lt;'>x</span>`, want: `<span title="&quot;&lt;">x</span>`},
+		{
+			desc:     "custom",
+			elements: map[string][]string{"u": {"class", "onclick"}},
+			give:     `<u class="a" onclick="x()">b</u><span>c</span>`,
+			want:     `<u class="a">b</u>c`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			s := AllowlistSanitizer{Elements: tt.elements}
+			got := string(s.Sanitize([]byte(tt.give)))
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, got, string(s.Sanitize([]byte(got))),
+				"sanitizing must be idempotent")
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package anchor
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Slugger turns header text into an identifier for the header.
+type Slugger interface {
+	// Slug returns an identifier for a header with the given text.
+	//
+	// The identifier may be empty
+	// if the text does not contain any usable characters.
+	Slug(text []byte) []byte
+}
+
+// SlugFunc is a [Slugger] implemented as a function.
+//
+//	anchor.IDs{
+//		Slugger: anchor.SlugFunc(func(text []byte) []byte {
+//			// ...
+//		}),
+//	}
+type SlugFunc func(text []byte) []byte
+
+var _ Slugger = SlugFunc(nil)
+
+// Slug calls the function.
+func (f SlugFunc) Slug(text []byte) []byte {
+	return f(text)
+}
+
+// UnicodeSlugger is a [Slugger] that supports headers
+// written in any script.
+//
+// Unlike Goldmark's default IDs, which drop all non-ASCII characters,
+// UnicodeSlugger keeps Unicode letters and digits by default.
+// For example, "Привет, мир" becomes "привет-мир".
+// Set Transliterator to generate ASCII-only identifiers instead.
+//
+// Identifiers are lowercase.
+// Runs of whitespace, '-', and '_' become a single '-',
+// and other punctuation is dropped.
+//
+// Use it with [IDs] to generate IDs for headers.
+type UnicodeSlugger struct {
+	// Form is the Unicode normalization form
+	// applied to header text before generating an identifier.
+	// This ensures that visually identical headers
+	// get identical identifiers.
+	//
+	// Use norm.NFKC to also fold compatibility characters
+	// like 'ﬁ' and full-width letters.
+	//
+	// Defaults to norm.NFC.
+	Form norm.Form
+
+	// Transliterator, if set, converts non-ASCII characters to ASCII.
+	//
+	// Characters that the Transliterator doesn't handle
+	// are stripped of diacritics (e.g. 'é' becomes 'e'),
+	// and dropped if they are still not ASCII.
+	//
+	// Defaults to keeping Unicode letters and digits as-is.
+	Transliterator Transliterator
+}
+
+var _ Slugger = (*UnicodeSlugger)(nil)
+
+// Slug generates an identifier from the given header text.
+func (s *UnicodeSlugger) Slug(text []byte) []byte {
+	text = s.Form.Bytes(text)
+
+	var (
+		out = make([]byte, 0, len(text))
+		sep bool // whether a separator is pending
+	)
+	emit := func(r rune) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), unicode.IsMark(r):
+			if sep && len(out) > 0 {
+				out = append(out, '-')
+			}
+			sep = false
+			out = utf8.AppendRune(out, unicode.ToLower(r))
+		case unicode.IsSpace(r), r == '-', r == '_':
+			sep = true
+		}
+	}
+
+	for len(text) > 0 {
+		r, size := utf8.DecodeRune(text)
+		text = text[size:]
+
+		if s.Transliterator == nil || r < utf8.RuneSelf {
+			emit(r)
+			continue
+		}
+
+		repl, ok := s.Transliterator.Transliterate(unicode.ToLower(r))
+		if !ok {
+			// Decompose the character and keep its ASCII base, if any.
+			repl = norm.NFD.String(string(r))
+		}
+		for _, rr := range repl {
+			if rr < utf8.RuneSelf {
+				emit(rr)
+			}
+		}
+	}
+
+	return out
+}
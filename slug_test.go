@@ -0,0 +1,95 @@
+package anchor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestUnicodeSlugger(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc    string
+		slugger UnicodeSlugger
+		give    string
+		want    string
+	}{
+		{desc: "ascii", give: "Foo Bar", want: "foo-bar"},
+		{desc: "punctuation", give: "Foo & Bar: Baz!", want: "foo-bar-baz"},
+		{desc: "separators", give: "  foo -- bar__baz  ", want: "foo-bar-baz"},
+		{desc: "empty", give: "!!!", want: ""},
+		{desc: "japanese", give: "はじめに", want: "はじめに"},
+		{desc: "russian", give: "Привет, мир", want: "привет-мир"},
+		{desc: "greek", give: "Καλημέρα κόσμε", want: "καλημέρα-κόσμε"},
+		{desc: "devanagari marks", give: "नमस्ते", want: "नमस्ते"},
+		{
+			desc: "nfc",
+			give: "Café", // 'e' + combining acute accent
+			want: "café",
+		},
+		{
+			desc:    "nfkc",
+			slugger: UnicodeSlugger{Form: norm.NFKC},
+			give:    "ﬁle Ｆｏｏ",
+			want:    "file-foo",
+		},
+		{
+			desc:    "cyrillic",
+			slugger: UnicodeSlugger{Transliterator: Cyrillic()},
+			give:    "Щедрый Жук",
+			want:    "shchedryy-zhuk",
+		},
+		{
+			desc:    "greek transliterated",
+			slugger: UnicodeSlugger{Transliterator: Greek()},
+			give:    "Καλημέρα κόσμε",
+			want:    "kalimera-kosme",
+		},
+		{
+			desc:    "pinyin",
+			slugger: UnicodeSlugger{Transliterator: Pinyin()},
+			give:    "安装指南",
+			want:    "an-zhuang-zhi-nan",
+		},
+		{
+			desc:    "pinyin mixed",
+			slugger: UnicodeSlugger{Transliterator: Pinyin()},
+			give:    "Go 语言 入门",
+			want:    "go-yu-yan-ru-men",
+		},
+		{
+			desc:    "diacritics stripped",
+			slugger: UnicodeSlugger{Transliterator: Cyrillic()},
+			give:    "Café Ærø",
+			want:    "cafe-r",
+		},
+		{
+			desc:    "unknown dropped",
+			slugger: UnicodeSlugger{Transliterator: Greek()},
+			give:    "Foo はじめに",
+			want:    "foo",
+		},
+		{
+			desc: "combined",
+			slugger: UnicodeSlugger{
+				Transliterator: Transliterators(
+					TransliterationTable{'ж': "j"},
+					Cyrillic(),
+				),
+			},
+			give: "Жук",
+			want: "juk",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, string(tt.slugger.Slug([]byte(tt.give))))
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package anchor
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Separators that may be used with [Spacing].
+const (
+	// NoBreakSpace is a non-breaking space (U+00A0).
+	NoBreakSpace = "\u00a0"
+
+	// ThinSpace is a narrow non-breaking space (U+202F).
+	// It's narrower than a regular space,
+	// and does not allow a line break.
+	ThinSpace = "\u202f"
+)
+
+const _defaultSeparator = " "
+
+// Spacing configures the whitespace between the heading text
+// and an anchor rendered inside the heading.
+//
+//	&anchor.Spacing{
+//		Separator: anchor.NoBreakSpace,
+//	}
+type Spacing struct {
+	// Separator is written between the heading text and the anchor.
+	// It's HTML-escaped before writing.
+	//
+	// Defaults to a single space.
+	Separator string
+
+	// NoSeparator places the anchor right next to the heading text,
+	// for example, if the anchor is positioned with CSS.
+	// Separator is ignored if this is set.
+	NoSeparator bool
+}
+
+func (s *Spacing) separator() []byte {
+	switch {
+	case s == nil || (len(s.Separator) == 0 && !s.NoSeparator):
+		return []byte(_defaultSeparator)
+	case s.NoSeparator:
+		return nil
+	default:
+		return util.EscapeHTML([]byte(s.Separator))
+	}
+}
+
+// _kindNoWrap is the NodeKind used by noWrap nodes.
+var _kindNoWrap = ast.NewNodeKind("AnchorNoWrap")
+
+// noWrap holds an anchor node and the word of the heading next to it.
+// It's rendered as a <span> that does not allow line breaks.
+type noWrap struct {
+	ast.BaseInline
+}
+
+func (*noWrap) Kind() ast.NodeKind { return _kindNoWrap }
+
+func (n *noWrap) Dump(src []byte, level int) {
+	ast.DumpHelper(n, src, level, nil, nil)
+}
+
+// wrapAdjacentWord moves the given anchor node, and the word of the heading
+// next to it, into a noWrap node.
+// It does nothing if the anchor was already wrapped.
+func wrapAdjacentWord(n *Node, src []byte) {
+	parent := n.Parent()
+	if parent == nil {
+		return
+	}
+	if _, ok := parent.(*noWrap); ok {
+		return
+	}
+
+	before := n.Position == Before
+	var word ast.Node
+	if before {
+		word = n.NextSibling()
+	} else {
+		word = n.PreviousSibling()
+	}
+	if word == nil {
+		return
+	}
+	if t, ok := word.(*ast.Text); ok {
+		splitWord(parent, t, src, before)
+	}
+
+	wrap := new(noWrap)
+	if before {
+		parent.InsertBefore(parent, n, wrap)
+		wrap.AppendChild(wrap, n)
+		wrap.AppendChild(wrap, word)
+	} else {
+		parent.InsertBefore(parent, word, wrap)
+		wrap.AppendChild(wrap, word)
+		wrap.AppendChild(wrap, n)
+	}
+}
+
+// unwrap replaces the given noWrap node with its children.
+func unwrap(wrap *noWrap) {
+	parent := wrap.Parent()
+	for c := wrap.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		parent.InsertBefore(parent, wrap, c)
+		c = next
+	}
+	parent.RemoveChild(parent, wrap)
+}
+
+// splitWord splits the given text node so that it holds only its first word
+// (if first is true) or its last word (otherwise).
+// The rest of the text is moved into a new sibling node.
+func splitWord(parent ast.Node, t *ast.Text, src []byte, first bool) {
+	seg := t.Segment
+	value := seg.Value(src)
+
+	if first {
+		i := bytes.IndexAny(bytes.TrimLeft(value, " \t"), " \t")
+		if i < 0 {
+			return
+		}
+		i += len(value) - len(bytes.TrimLeft(value, " \t"))
+
+		rest := ast.NewTextSegment(text.NewSegment(seg.Start+i, seg.Stop))
+		rest.SetRaw(t.IsRaw())
+		rest.SetSoftLineBreak(t.SoftLineBreak())
+		rest.SetHardLineBreak(t.HardLineBreak())
+		t.SetSoftLineBreak(false)
+		t.SetHardLineBreak(false)
+		t.Segment = seg.WithStop(seg.Start + i)
+		parent.InsertAfter(parent, t, rest)
+		return
+	}
+
+	i := bytes.LastIndexAny(bytes.TrimRight(value, " \t"), " \t")
+	if i < 0 {
+		return
+	}
+
+	rest := ast.NewTextSegment(seg.WithStop(seg.Start + i + 1))
+	rest.SetRaw(t.IsRaw())
+	t.Segment = text.NewSegment(seg.Start+i+1, seg.Stop)
+	parent.InsertBefore(parent, t, rest)
+}
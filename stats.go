@@ -0,0 +1,247 @@
+package anchor
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+const _defaultWordsPerMinute = 200
+
+// SectionStats holds statistics about the section of a document
+// introduced by a header.
+//
+// A section starts at its header and ends before the next header
+// of the same or a higher level.
+// For example, a level 2 section ends at the next level 1 or 2 header.
+// Sections include the contents of their subsections.
+type SectionStats struct {
+	// Words is the number of words in the section,
+	// including the header text.
+	Words int
+
+	// CodeBlocks is the number of fenced and indented code blocks
+	// in the section.
+	CodeBlocks int
+
+	// Links is the number of outbound links in the section.
+	// Links to fragments in the same document (e.g. "#foo")
+	// are not counted.
+	Links int
+
+	// ReadingTime is the estimated time to read the section.
+	ReadingTime time.Duration
+}
+
+// ReadingMinutes reports the estimated reading time of the section
+// in whole minutes, rounded up.
+func (s *SectionStats) ReadingMinutes() int {
+	return int((s.ReadingTime + time.Minute - 1) / time.Minute)
+}
+
+// Stats configures the computation of [SectionStats]
+// for headers in a document.
+//
+// Pass this into [Extender] or [Transformer] to enable statistics.
+// Statistics are made available to Texters and Attributers
+// through [HeaderInfo], and recorded on each anchor [Node].
+//
+//	anchor.Extender{
+//		Stats: &anchor.Stats{},
+//	}
+type Stats struct {
+	// WordsPerMinute is the reading speed
+	// used to estimate reading time.
+	//
+	// Defaults to 200.
+	WordsPerMinute int
+
+	// CountCode specifies whether words inside code blocks and code spans
+	// should be counted.
+	//
+	// Defaults to false.
+	CountCode bool
+
+	// CountHTML specifies whether words inside raw HTML
+	// should be counted.
+	//
+	// Defaults to false.
+	CountHTML bool
+
+	// ReadingTimeAttribute specifies whether anchors should have
+	// a 'data-reading-time' attribute
+	// with the reading time of the section in minutes.
+	//
+	// Defaults to false.
+	ReadingTimeAttribute bool
+}
+
+// compute computes statistics for all sections in the document.
+func (s *Stats) compute(doc ast.Node, src []byte) map[*ast.Heading]*SectionStats {
+	wpm := s.WordsPerMinute
+	if wpm <= 0 {
+		wpm = _defaultWordsPerMinute
+	}
+
+	sections := make(map[*ast.Heading]*SectionStats)
+
+	// Sections that contain the current node, outermost first.
+	var open []*SectionStats
+	var levels []int
+	add := func(f func(*SectionStats)) {
+		for _, st := range open {
+			f(st)
+		}
+	}
+
+	// Words may span multiple inline nodes, e.g. "foo*bar*".
+	// The counter carries over between them until the next block.
+	var wc wordCounter
+	_ = ast.Walk(doc, func(n ast.Node, enter bool) (ast.WalkStatus, error) {
+		if !enter {
+			return ast.WalkContinue, nil
+		}
+		if n.Type() == ast.TypeBlock {
+			wc.Reset()
+		}
+
+		switch n := n.(type) {
+		case *ast.Heading:
+			for len(levels) > 0 && levels[len(levels)-1] >= n.Level {
+				open, levels = open[:len(open)-1], levels[:len(levels)-1]
+			}
+			st := new(SectionStats)
+			sections[n] = st
+			open, levels = append(open, st), append(levels, n.Level)
+
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			words := 0
+			if s.CountCode {
+				words = countLineWords(n, src)
+			}
+			add(func(st *SectionStats) {
+				st.CodeBlocks++
+				st.Words += words
+			})
+			return ast.WalkSkipChildren, nil
+
+		case *ast.CodeSpan:
+			if !s.CountCode {
+				return ast.WalkSkipChildren, nil
+			}
+
+		case *ast.HTMLBlock:
+			if s.CountHTML {
+				words := countLineWords(n, src)
+				add(func(st *SectionStats) { st.Words += words })
+			}
+			return ast.WalkSkipChildren, nil
+
+		case *ast.RawHTML:
+			if s.CountHTML {
+				words := 0
+				for i := 0; i < n.Segments.Len(); i++ {
+					seg := n.Segments.At(i)
+					words += countWords(seg.Value(src))
+				}
+				add(func(st *SectionStats) { st.Words += words })
+			}
+			return ast.WalkSkipChildren, nil
+
+		case *ast.Link:
+			if !bytes.HasPrefix(n.Destination, []byte("#")) {
+				add(func(st *SectionStats) { st.Links++ })
+			}
+
+		case *ast.AutoLink:
+			words := wc.Count(n.Label(src))
+			add(func(st *SectionStats) {
+				st.Links++
+				st.Words += words
+			})
+
+		case *ast.Text:
+			words := wc.Count(n.Value(src))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				wc.Reset()
+			}
+			add(func(st *SectionStats) { st.Words += words })
+
+		case *ast.String:
+			if n.IsCode() && !s.CountCode {
+				break
+			}
+			words := wc.Count(n.Value)
+			add(func(st *SectionStats) { st.Words += words })
+
+		case *Node:
+			return ast.WalkSkipChildren, nil
+		}
+
+		return ast.WalkContinue, nil
+	})
+	// Walk never fails because the walker never returns an error.
+
+	for _, st := range sections {
+		st.ReadingTime = time.Duration(st.Words) * time.Minute / time.Duration(wpm)
+	}
+
+	return sections
+}
+
+// readingTimeAttribute returns the value of the 'data-reading-time'
+// attribute for the given section.
+func readingTimeAttribute(st *SectionStats) []byte {
+	return strconv.AppendInt(nil, int64(st.ReadingMinutes()), 10)
+}
+
+// countLineWords counts the words in the lines of a block node.
+func countLineWords(n ast.Node, src []byte) int {
+	var words int
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		seg := lines.At(i)
+		words += countWords(seg.Value(src))
+	}
+	return words
+}
+
+// countWords counts the whitespace-separated words in b.
+func countWords(b []byte) int {
+	var wc wordCounter
+	return wc.Count(b)
+}
+
+// wordCounter counts whitespace-separated words
+// across multiple consecutive pieces of text.
+type wordCounter struct {
+	inWord bool
+}
+
+// Count reports the number of new words that start in b.
+// A word that continues from the previous call is not counted again.
+func (wc *wordCounter) Count(b []byte) int {
+	var words int
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		b = b[size:]
+
+		if unicode.IsSpace(r) {
+			wc.inWord = false
+		} else if !wc.inWord {
+			wc.inWord = true
+			words++
+		}
+	}
+	return words
+}
+
+// Reset marks the end of a piece of text.
+// The next call to Count will start a new word.
+func (wc *wordCounter) Reset() {
+	wc.inWord = false
+}
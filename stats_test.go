@@ -0,0 +1,198 @@
+package anchor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc  string
+		stats Stats
+		give  []string
+		want  []SectionStats
+	}{
+		{
+			desc: "sections",
+			give: []string{
+				"# Foo",
+				"",
+				"One two three.",
+				"",
+				"## Bar",
+				"",
+				"Four [five](http://example.com) [six](#foo).",
+				"",
+				"### Baz",
+				"",
+				"<http://example.com>",
+				"",
+				"## Qux",
+				"",
+				"Seven.",
+			},
+			want: []SectionStats{
+				{Words: 12, Links: 2},
+				{Words: 6, Links: 2},
+				{Words: 2, Links: 1},
+				{Words: 2},
+			},
+		},
+		{
+			// Text between inline HTML tags is still counted.
+			desc: "skip code and html",
+			give: []string{
+				"# Foo",
+				"",
+				"Use `foo bar` here.",
+				"",
+				"```",
+				"foo bar baz",
+				"```",
+				"",
+				"    qux quux",
+				"",
+				"<div>",
+				"html words",
+				"</div>",
+				"",
+				"inline <b>raw html</b>",
+			},
+			want: []SectionStats{
+				{Words: 6, CodeBlocks: 2},
+			},
+		},
+		{
+			desc:  "count code and html",
+			stats: Stats{CountCode: true, CountHTML: true},
+			give: []string{
+				"# Foo",
+				"",
+				"Use `foo bar` here.",
+				"",
+				"```",
+				"foo bar baz",
+				"```",
+				"",
+				"<div>",
+				"html words",
+				"</div>",
+			},
+			want: []SectionStats{
+				{Words: 12, CodeBlocks: 1},
+			},
+		},
+		{
+			desc:  "reading time",
+			stats: Stats{WordsPerMinute: 2},
+			give: []string{
+				"# Foo",
+				"",
+				"One two three four.",
+			},
+			want: []SectionStats{
+				{Words: 5, ReadingTime: 150 * time.Second},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			md := goldmark.New(
+				goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+				goldmark.WithExtensions(&Extender{
+					Stats: &tt.stats,
+				}),
+			)
+
+			ctx := parser.NewContext()
+			src := []byte(strings.Join(tt.give, "\n") + "\n")
+			require.NoError(t, md.Convert(src, new(bytes.Buffer), parser.WithContext(ctx)))
+
+			var got []SectionStats
+			for _, n := range Nodes(ctx) {
+				require.NotNil(t, n.Section, "node %q", n.ID)
+				st := *n.Section
+				if tt.stats.WordsPerMinute == 0 {
+					// Only compare reading time if it was customized.
+					st.ReadingTime = 0
+				}
+				got = append(got, st)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStats_readingTimeAttribute(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithExtensions(&Extender{
+			Stats: &Stats{
+				WordsPerMinute:       3,
+				ReadingTimeAttribute: true,
+			},
+		}),
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, md.Convert([]byte("# Foo\n\nBar baz qux.\n\n## Quux\n"), &buf))
+	assert.Equal(t,
+		`<h1 id="foo">Foo <a class="anchor" data-reading-time="2" href="#foo">¶</a></h1>`+"\n"+
+			`<p>Bar baz qux.</p>`+"\n"+
+			`<h2 id="quux">Quux <a class="anchor" data-reading-time="1" href="#quux">¶</a></h2>`+"\n",
+		buf.String())
+}
+
+func TestNodes_noStats(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithExtensions(&Extender{}),
+	)
+
+	ctx := parser.NewContext()
+	require.NoError(t, md.Convert([]byte("# Foo\n\n## Bar\n"), new(bytes.Buffer), parser.WithContext(ctx)))
+
+	nodes := Nodes(ctx)
+	require.Len(t, nodes, 2)
+	assert.Equal(t, "foo", string(nodes[0].ID))
+	assert.Equal(t, "bar", string(nodes[1].ID))
+	assert.Nil(t, nodes[0].Section)
+	assert.Nil(t, nodes[1].Section)
+}
+
+func TestCountWords(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		give string
+		want int
+	}{
+		{"", 0},
+		{"   ", 0},
+		{"foo", 1},
+		{" foo  bar\tbaz\n", 3},
+		{"héllo wörld", 2},
+		{"foo bar", 2},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, countWords([]byte(tt.give)), "countWords(%q)", tt.give)
+	}
+}
@@ -0,0 +1,9 @@
+// Package theme provides stylesheets for anchors
+// generated by goldmark-anchor.
+//
+// The stylesheets target anchors with the default "anchor" class.
+// They respect the user's color scheme and reduced motion preferences.
+//
+// Include a stylesheet in a page with [Theme.StyleTag],
+// or serve [FS] and reference a stylesheet with [Theme.LinkTag].
+package theme
@@ -0,0 +1,96 @@
+package theme
+
+import (
+	"embed"
+	"fmt"
+	"html"
+	"io/fs"
+	"strings"
+)
+
+// FS holds the stylesheets for all themes.
+// The stylesheet for a theme is at the path reported by [Theme.Filename].
+//
+// Serve it to reference stylesheets with [Theme.LinkTag].
+//
+//	http.Handle("/static/", http.StripPrefix("/static/", http.FileServerFS(theme.FS)))
+//
+//go:embed *.css
+var FS embed.FS
+
+// Theme is a stylesheet for anchors.
+type Theme string
+
+const (
+	// GitHub hides anchors until their header is hovered,
+	// similar to READMEs on GitHub.
+	// Anchors are also shown when they receive keyboard focus.
+	GitHub Theme = "github"
+
+	// LeftGutter places anchors in the margin to the left of their header,
+	// and hides them until the header is hovered.
+	//
+	// This requires anchors placed inside the header element.
+	// Use it with [go.abhg.dev/goldmark/anchor.Spacing.NoSeparator]
+	// to avoid leaving a space where the anchor would have been.
+	LeftGutter Theme = "left-gutter"
+
+	// Muted always shows anchors, in a muted color
+	// that is highlighted when the anchor is hovered.
+	Muted Theme = "muted"
+)
+
+// Themes returns a list of all available themes.
+func Themes() []Theme {
+	return []Theme{GitHub, LeftGutter, Muted}
+}
+
+// Filename returns the name of the stylesheet for this theme in [FS].
+func (t Theme) Filename() string {
+	return string(t) + ".css"
+}
+
+// CSS returns the stylesheet for this theme.
+// It returns an error if the theme does not exist.
+func (t Theme) CSS() ([]byte, error) {
+	b, err := fs.ReadFile(FS, t.Filename())
+	if err != nil {
+		return nil, fmt.Errorf("theme %q: %w", string(t), err)
+	}
+	return b, nil
+}
+
+// StyleTag returns a <style> element holding the stylesheet for this theme.
+// Add it to the <head> of the page.
+// It returns an error if the theme does not exist.
+func (t Theme) StyleTag() (string, error) {
+	css, err := t.CSS()
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<style>\n")
+	sb.Write(css)
+	sb.WriteString("</style>")
+	return sb.String(), nil
+}
+
+// LinkTag returns a <link> element that references the stylesheet
+// for this theme served from FS under the given URL prefix.
+// It returns an error if the theme does not exist.
+//
+//	theme.GitHub.LinkTag("/static/")
+//	// <link rel="stylesheet" href="/static/github.css">
+func (t Theme) LinkTag(prefix string) (string, error) {
+	if _, err := fs.Stat(FS, t.Filename()); err != nil {
+		return "", fmt.Errorf("theme %q: %w", string(t), err)
+	}
+
+	href := prefix
+	if len(href) > 0 && !strings.HasSuffix(href, "/") {
+		href += "/"
+	}
+	href += t.Filename()
+	return `<link rel="stylesheet" href="` + html.EscapeString(href) + `">`, nil
+}
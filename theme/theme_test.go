@@ -0,0 +1,118 @@
+package theme_test
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.abhg.dev/goldmark/anchor/theme"
+)
+
+func TestThemes(t *testing.T) {
+	t.Parallel()
+
+	for _, th := range theme.Themes() {
+		th := th
+		t.Run(string(th), func(t *testing.T) {
+			t.Parallel()
+
+			css, err := th.CSS()
+			require.NoError(t, err)
+
+			got := string(css)
+			assert.Contains(t, got, ".anchor")
+			assert.Contains(t, got, "@media (prefers-reduced-motion: reduce)")
+			assert.Contains(t, got, "@media (prefers-color-scheme: dark)")
+			assert.Equal(t,
+				strings.Count(got, "{"), strings.Count(got, "}"),
+				"unbalanced braces")
+		})
+	}
+}
+
+func TestThemes_allEmbedded(t *testing.T) {
+	t.Parallel()
+
+	files, err := fs.Glob(theme.FS, "*.css")
+	require.NoError(t, err)
+
+	var want []string
+	for _, th := range theme.Themes() {
+		want = append(want, th.Filename())
+	}
+	assert.ElementsMatch(t, want, files)
+}
+
+func TestTheme_unknown(t *testing.T) {
+	t.Parallel()
+
+	_, err := theme.Theme("nope").CSS()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `theme "nope"`)
+
+	_, err = theme.Theme("nope").StyleTag()
+	require.Error(t, err)
+
+	_, err = theme.Theme("nope").LinkTag("/static/")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `theme "nope"`)
+}
+
+func TestTheme_StyleTag(t *testing.T) {
+	t.Parallel()
+
+	css, err := theme.Muted.CSS()
+	require.NoError(t, err)
+
+	got, err := theme.Muted.StyleTag()
+	require.NoError(t, err)
+	assert.Equal(t, "<style>\n"+string(css)+"</style>", got)
+}
+
+func TestTheme_LinkTag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc   string
+		theme  theme.Theme
+		prefix string
+		want   string
+	}{
+		{
+			desc:  "no prefix",
+			theme: theme.GitHub,
+			want:  `<link rel="stylesheet" href="github.css">`,
+		},
+		{
+			desc:   "prefix",
+			theme:  theme.LeftGutter,
+			prefix: "/static/",
+			want:   `<link rel="stylesheet" href="/static/left-gutter.css">`,
+		},
+		{
+			desc:   "prefix without slash",
+			theme:  theme.Muted,
+			prefix: "https://example.com/css",
+			want:   `<link rel="stylesheet" href="https://example.com/css/muted.css">`,
+		},
+		{
+			desc:   "escaped",
+			theme:  theme.GitHub,
+			prefix: `/a"b&c/`,
+			want:   `<link rel="stylesheet" href="/a&#34;b&amp;c/github.css">`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.theme.LinkTag(tt.prefix)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
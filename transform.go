@@ -1,6 +1,8 @@
 package anchor
 
 import (
+	"slices"
+
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/text"
@@ -20,6 +22,39 @@ type HeaderInfo struct {
 	// Identifier for the header on the page.
 	// This will typically become part of the URL fragment.
 	ID []byte
+
+	// Section holds statistics about the section introduced by this header.
+	//
+	// This is nil unless statistics are enabled with [Stats].
+	Section *SectionStats
+
+	heading   ast.Node
+	source    []byte
+	plainText *PlainText
+}
+
+// Text returns the plain text of the header
+// as reported by [PlainText.Text].
+// Emoji are handled according to the Emoji setting of the [Transformer].
+//
+// This is computed each time it's called.
+func (h *HeaderInfo) Text() []byte {
+	if h.heading == nil {
+		return nil
+	}
+	return h.plainText.Text(h.heading, h.source)
+}
+
+// SlugSource returns text of the header suitable for generating
+// an identifier with a [Slugger],
+// as reported by [PlainText.SlugSource].
+//
+// This is computed each time it's called.
+func (h *HeaderInfo) SlugSource() []byte {
+	if h.heading == nil {
+		return nil
+	}
+	return h.plainText.SlugSource(h.heading, h.source)
 }
 
 // Texter determines the anchor text.
@@ -56,6 +91,35 @@ func (t textTexter) AnchorText(*HeaderInfo) []byte {
 	return []byte(t)
 }
 
+// NodeTexter is a Texter that builds the anchor text
+// from Markdown inline nodes instead of raw bytes.
+// Use [MarkdownText] to build one from a Markdown string.
+//
+// If the Texter of a [Transformer] implements NodeTexter,
+// the nodes returned by AnchorNodes become children of the anchor [Node].
+// [Renderer] renders them through goldmark like the rest of the document,
+// so the HTML in them is safe without Unsafe.
+//
+// AnchorText is still used for Node.Value,
+// which renderers that don't render children,
+// like [TerminalRenderer], use as the anchor text.
+// If AnchorText is empty, Node.Value is the plain text of the nodes.
+type NodeTexter interface {
+	Texter
+
+	// AnchorNodes returns new inline nodes for the anchor text
+	// of the provided header.
+	// Each call must return new nodes without a parent.
+	//
+	// The nodes are rendered with the source of the document,
+	// so nodes like ast.Text must refer only to text in the document.
+	// Use ast.String for other text.
+	//
+	// If AnchorNodes returns an empty slice or nil,
+	// AnchorText is used instead.
+	AnchorNodes(*HeaderInfo) []ast.Node
+}
+
 // Position specifies where inside a heading we should place an anchor [Node].
 type Position int
 
@@ -81,6 +145,9 @@ type Attributer interface {
 	//
 	// If AnchorAttributes returns an empty map or nil,
 	// no attributes will be added.
+	//
+	// Attributes are added to the anchor sorted by name
+	// so that the rendered HTML is the same every time.
 	AnchorAttributes(*HeaderInfo) map[string]string
 }
 
@@ -121,6 +188,93 @@ type Transformer struct {
 	// Defaults to adding a 'class="anchor"' attribute
 	// for all headers if unset.
 	Attributer Attributer
+
+	// LinkPolicy specifies how headers that contain links are handled.
+	//
+	// Defaults to LinkKeep.
+	LinkPolicy LinkPolicy
+
+	// Stats configures per-section statistics.
+	//
+	// Statistics are not computed if this is unset.
+	Stats *Stats
+
+	// IDPrefix is added to the IDs of all headers in the document,
+	// including IDs specified explicitly in the document.
+	// The anchors link to the prefixed IDs.
+	//
+	// Use this to avoid collisions between header IDs
+	// when rendering multiple documents into the same page.
+	//
+	// IDPrefix is added in addition to the Prefix of [IDs],
+	// so generated IDs get both, with IDPrefix first.
+	//
+	// If a document is transformed more than once with the same context,
+	// for example, because its parser has two Transformers,
+	// only the first IDPrefix is added.
+	IDPrefix string
+
+	// RewriteFragmentLinks specifies whether links inside the document
+	// that point to its headers (e.g. "[see](#install)")
+	// should be updated to use the prefixed IDs.
+	//
+	// This has no effect if IDPrefix is unset.
+	RewriteFragmentLinks bool
+
+	// LevelOffset shifts the levels of all headers in the document.
+	// For example, with a LevelOffset of 1, '#' headers become <h2>.
+	// Levels are clamped to the range 1 to 6.
+	//
+	// Anchors and the HeaderInfo passed to Texter and Attributer
+	// use the shifted levels.
+	//
+	// Use this when embedding a document inside a page
+	// that already has its own headers.
+	//
+	// Like IDPrefix, only the first LevelOffset is applied
+	// if a document is transformed more than once with the same context.
+	LevelOffset int
+
+	// Levels restricts anchors to headers of the given levels.
+	//
+	// Defaults to generating anchors for headers of all levels.
+	Levels []int
+
+	// Metadata retrieves metadata for the document being transformed,
+	// for example, from its front matter.
+	// The following keys override settings for that document:
+	//
+	//	anchors: false         # don't generate anchors
+	//	anchor_text: "#"       # use a constant anchor text
+	//	anchor_levels: [2, 3]  # generate anchors only for these levels
+	//
+	// Use meta.Get from goldmark-meta to read front matter.
+	Metadata MetadataFunc
+
+	// Emoji specifies how emoji are handled
+	// in the header text reported by HeaderInfo.Text.
+	//
+	// Defaults to EmojiKeep.
+	Emoji EmojiPolicy
+
+	// EmojiName names emoji written directly in header text
+	// if Emoji is EmojiShortcode.
+	// See [PlainText.EmojiName] for details.
+	EmojiName func(emoji string) string
+
+	// NoWrap groups each anchor with the word of the heading next to it.
+	// The Renderer wraps both in a <span style="white-space: nowrap">
+	// so that the anchor never wraps onto a line of its own.
+	//
+	//	<h1 id="foo-bar">Foo <span style="white-space: nowrap">Bar <a href="#foo-bar">¶</a></span></h1>
+	//
+	// If the heading text next to the anchor is not plain text,
+	// for example, if it's emphasized,
+	// the span wraps all of it.
+	//
+	// Don't use this with a Renderer that places anchors
+	// outside the heading element.
+	NoWrap bool
 }
 
 var _ parser.ASTTransformer = (*Transformer)(nil)
@@ -129,11 +283,25 @@ var _ parser.ASTTransformer = (*Transformer)(nil)
 //
 // This method is typically called by Goldmark
 // and should not need to be invoked directly.
-func (t *Transformer) Transform(doc *ast.Document, _ text.Reader, _ parser.Context) {
+func (t *Transformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
 	tr := transform{
-		Attributer: t.Attributer,
-		Position:   t.Position,
-		Texter:     t.Texter,
+		Attributer:           t.Attributer,
+		Position:             t.Position,
+		Texter:               t.Texter,
+		LinkPolicy:           t.LinkPolicy,
+		Levels:               t.Levels,
+		IDPrefix:             t.IDPrefix,
+		RewriteFragmentLinks: t.RewriteFragmentLinks,
+		LevelOffset:          t.LevelOffset,
+		NoWrap:               t.NoWrap,
+		Stats:                t.Stats,
+		PlainText:            PlainText{Emoji: t.Emoji, EmojiName: t.EmojiName},
+		Source:               reader.Source(),
+	}
+	if pc != nil {
+		if opts := getContextOptions(pc); opts != nil {
+			opts.apply(&tr)
+		}
 	}
 	if tr.Attributer == nil {
 		tr.Attributer = _defaultAttributer
@@ -141,9 +309,97 @@ func (t *Transformer) Transform(doc *ast.Document, _ text.Reader, _ parser.Conte
 	if tr.Texter == nil {
 		tr.Texter = _defaultTexter
 	}
+	// Levels and IDs are changed only once per document
+	// even if it's transformed again,
+	// e.g. because a parser has two Transformers.
+	if tr.LevelOffset != 0 && markTransformed(pc, _levelsShiftedKey, doc) {
+		shiftLevels(doc, tr.LevelOffset)
+	}
+	if len(tr.IDPrefix) > 0 && markTransformed(pc, _idsPrefixedKey, doc) {
+		prefix := []byte(tr.IDPrefix)
+		ids := prefixIDs(doc, prefix)
+		if tr.RewriteFragmentLinks {
+			rewriteFragmentLinks(doc, prefix, ids)
+		}
+	}
+	if tr.Stats != nil {
+		tr.Sections = tr.Stats.compute(doc, tr.Source)
+	}
 
-	_ = ast.Walk(doc, tr.Visit)
-	// Visit always returns a nil error.
+	enabled := true
+	if t.Metadata != nil && pc != nil {
+		enabled = tr.applyMetadata(t.Metadata(pc))
+	}
+
+	if enabled {
+		tr.cacheConstants()
+		_ = ast.Walk(doc, tr.Visit)
+		// Visit always returns a nil error.
+	}
+
+	if pc != nil {
+		pc.Set(_nodesKey, tr.Nodes)
+	}
+}
+
+var _nodesKey = parser.NewContextKey()
+
+// Nodes returns the anchor [Node]s that were added to a document
+// parsed with the given context, in the order they appear in the document.
+//
+// Use this to collect metadata about the anchors in a document.
+//
+//	ctx := parser.NewContext()
+//	if err := md.Convert(src, &buf, parser.WithContext(ctx)); err != nil {
+//		// ...
+//	}
+//	for _, n := range anchor.Nodes(ctx) {
+//		fmt.Println(string(n.ID), n.Section.Words)
+//	}
+func Nodes(pc parser.Context) []*Node {
+	nodes, _ := pc.Get(_nodesKey).([]*Node)
+	return nodes
+}
+
+// Context keys that record the document
+// whose levels were shifted or IDs were prefixed.
+var (
+	_levelsShiftedKey = parser.NewContextKey()
+	_idsPrefixedKey   = parser.NewContextKey()
+)
+
+// markTransformed records in the context
+// that the given change was made to the document.
+// It reports false if the change was already made.
+//
+// Without a context, changes are always made.
+func markTransformed(pc parser.Context, key parser.ContextKey, doc *ast.Document) bool {
+	if pc == nil {
+		return true
+	}
+	if marked, _ := pc.Get(key).(*ast.Document); marked == doc {
+		return false
+	}
+	pc.Set(key, doc)
+	return true
+}
+
+// shiftLevels adds offset to the levels of all headers in the document,
+// keeping them within the range of HTML headers.
+func shiftLevels(doc ast.Node, offset int) {
+	_ = ast.Walk(doc, func(n ast.Node, enter bool) (ast.WalkStatus, error) {
+		if !enter {
+			return ast.WalkContinue, nil
+		}
+		h, ok := n.(*ast.Heading)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+
+		h.Level = min(max(h.Level+offset, 1), 6)
+		return ast.WalkSkipChildren, nil
+	})
+	// Walk never fails because the walker never returns an error.
 }
 
 // transform holds state for a single transformation traversal.
@@ -151,6 +407,50 @@ type transform struct {
 	Texter     Texter
 	Position   Position
 	Attributer Attributer
+	LinkPolicy LinkPolicy
+	Stats      *Stats
+	Levels     []int
+
+	IDPrefix             string
+	RewriteFragmentLinks bool
+	LevelOffset          int
+	NoWrap               bool
+
+	// PlainText extracts the text of headers for HeaderInfo.
+	PlainText PlainText
+
+	// render holds Renderer settings overridden for this conversion,
+	// or nil if there are none.
+	// It's attached to all anchor nodes.
+	render *renderOptions
+
+	// Source is the Markdown source of the document.
+	Source []byte
+
+	// Sections holds statistics for all headers in the document
+	// if Stats is non-nil.
+	Sections map[*ast.Heading]*SectionStats
+
+	// Nodes is the list of anchor nodes added to the document so far.
+	Nodes []*Node
+
+	// constText is the anchor text for all headers
+	// if hasConstText is set.
+	constText    []byte
+	hasConstText bool
+
+	// constAttrs holds the attributes for all anchors
+	// if the Attributer is constant.
+	constAttrs []ast.Attribute
+
+	// attrNames is a buffer for the sorted names of attributes
+	// returned by the Attributer.
+	attrNames []string
+
+	// nodeChunk holds preallocated anchor nodes for newNode,
+	// and nodeChunkSize is the size it was allocated with.
+	nodeChunk     []Node
+	nodeChunkSize int
 }
 
 func (t *transform) Visit(n ast.Node, enter bool) (ast.WalkStatus, error) {
@@ -177,25 +477,103 @@ func (t *transform) transform(h *ast.Heading) {
 		return
 	}
 
-	info := HeaderInfo{
-		Level: h.Level,
-		ID:    id,
+	if len(t.Levels) > 0 && !slices.Contains(t.Levels, h.Level) {
+		return
+	}
+
+	switch t.LinkPolicy {
+	case LinkSkip:
+		if hasLink(h) {
+			return
+		}
+	case LinkUnwrap:
+		unwrapLinks(h, t.Source)
 	}
 
-	text := t.Texter.AnchorText(&info)
+	// HeaderInfo is built only if the Texter or Attributer need it.
+	var info *HeaderInfo
+
+	text := t.constText
+	var children []ast.Node
+	if !t.hasConstText {
+		info = t.headerInfo(h, id)
+		if nt, ok := t.Texter.(NodeTexter); ok {
+			children = nt.AnchorNodes(info)
+		}
+		text = t.Texter.AnchorText(info)
+		if len(text) == 0 && len(children) > 0 {
+			var w plainTextWriter
+			for _, c := range children {
+				w.WriteNode(c, t.Source)
+			}
+			text = w.Bytes()
+		}
+	}
 	if len(text) == 0 {
 		return
 	}
 
-	n := &Node{
-		ID:    id,
-		Level: h.Level,
-		Value: text,
+	// If the heading already has an anchor, e.g. because the document
+	// was transformed before, update it instead of adding another.
+	n := headingAnchor(h)
+	if n == nil {
+		n = t.newNode()
+	} else {
+		n.RemoveAttributes()
+	}
+	n.ID = id
+	n.Level = h.Level
+	n.Value = text
+	if n.HasChildren() {
+		n.RemoveChildren(n)
+	}
+	for _, c := range children {
+		n.AppendChild(n, c)
+	}
+	n.Section = t.Sections[h]
+	n.render = t.render
+
+	if t.constAttrs != nil {
+		for _, attr := range t.constAttrs {
+			n.SetAttribute(attr.Name, attr.Value)
+		}
+	} else {
+		if info == nil {
+			info = t.headerInfo(h, id)
+		}
+		attrs := t.Attributer.AnchorAttributes(info)
+		t.attrNames = sortedKeys(t.attrNames[:0], attrs)
+		for _, name := range t.attrNames {
+			n.SetAttributeString(name, []byte(attrs[name]))
+		}
 	}
+	if t.Stats != nil && t.Stats.ReadingTimeAttribute && n.Section != nil {
+		n.SetAttributeString("data-reading-time", readingTimeAttribute(n.Section))
+	}
+	t.Nodes = append(t.Nodes, n)
 
-	for name, value := range t.Attributer.AnchorAttributes(&info) {
-		n.SetAttributeString(name, []byte(value))
+	t.place(h, n)
+	if t.NoWrap {
+		wrapAdjacentWord(n, t.Source)
 	}
+}
+
+// place adds the given anchor node to the heading
+// at the configured position,
+// moving it if it's already elsewhere in the heading.
+func (t *transform) place(h *ast.Heading, n *Node) {
+	if parent := n.Parent(); parent != nil {
+		if n.Position == t.Position {
+			return
+		}
+		// Move the anchor to its new position below.
+		if wrap, ok := parent.(*noWrap); ok {
+			unwrap(wrap)
+			parent = h
+		}
+		parent.RemoveChild(parent, n)
+	}
+	n.Position = t.Position
 
 	// If the header has no children yet, just append the anchor.
 	if h.ChildCount() == 0 {
@@ -209,3 +587,66 @@ func (t *transform) transform(h *ast.Heading) {
 		h.InsertAfter(h, h.LastChild(), n)
 	}
 }
+
+// headerInfo builds the HeaderInfo for the given heading.
+func (t *transform) headerInfo(h *ast.Heading, id []byte) *HeaderInfo {
+	return &HeaderInfo{
+		Level:     h.Level,
+		ID:        id,
+		Section:   t.Sections[h],
+		heading:   h,
+		source:    t.Source,
+		plainText: &t.PlainText,
+	}
+}
+
+// cacheConstants records the results of the Texter and Attributer
+// if they are constant so that they aren't re-computed for every header.
+//
+// This must be called after the Texter and Attributer are finalized.
+func (t *transform) cacheConstants() {
+	if text, ok := t.Texter.(textTexter); ok {
+		t.constText = text
+		t.hasConstText = true
+	}
+
+	if attrs, ok := t.Attributer.(Attributes); ok {
+		t.constAttrs = make([]ast.Attribute, 0, len(attrs))
+		for _, name := range sortedKeys(nil, attrs) {
+			t.constAttrs = append(t.constAttrs, ast.Attribute{
+				Name:  []byte(name),
+				Value: []byte(attrs[name]),
+			})
+		}
+	}
+}
+
+// sortedKeys appends the keys of m to dst in sorted order
+// and returns the result.
+func sortedKeys(dst []string, m map[string]string) []string {
+	for k := range m {
+		dst = append(dst, k)
+	}
+	slices.Sort(dst)
+	return dst
+}
+
+// _maxNodeChunk is the maximum number of anchor nodes
+// allocated together by newNode.
+const _maxNodeChunk = 256
+
+// newNode returns a new anchor node.
+//
+// Nodes are allocated in chunks of increasing size
+// to reduce the number of allocations for large documents.
+func (t *transform) newNode() *Node {
+	if len(t.nodeChunk) == 0 {
+		// Slicing nodeChunk shrinks its capacity,
+		// so the size of the last chunk is tracked separately.
+		t.nodeChunkSize = min(max(2*t.nodeChunkSize, 8), _maxNodeChunk)
+		t.nodeChunk = make([]Node, t.nodeChunkSize)
+	}
+	n := &t.nodeChunk[0]
+	t.nodeChunk = t.nodeChunk[1:]
+	return n
+}
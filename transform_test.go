@@ -1,6 +1,9 @@
 package anchor
 
 import (
+	"bytes"
+	"fmt"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -9,8 +12,10 @@ import (
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/text"
 	"github.com/yuin/goldmark/util"
+	"go.abhg.dev/goldmark/anchor/emojiname"
 )
 
 func TestTransform(t *testing.T) {
@@ -30,8 +35,10 @@ func TestTransform(t *testing.T) {
 		give []string
 		want []*anchor
 
-		pos  Position
-		text Texter
+		pos         Position
+		text        Texter
+		links       LinkPolicy
+		levelOffset int
 	}{
 		{
 			desc: "simple",
@@ -120,6 +127,84 @@ func TestTransform(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:  "skip links",
+			links: LinkSkip,
+			give: []string{
+				"# [Foo](http://example.com)",
+				"",
+				"## Bar *<http://example.com>*",
+				"",
+				"### Baz",
+			},
+			want: []*anchor{
+				nil,
+				nil,
+				{
+					ID:       "baz",
+					Level:    3,
+					Value:    defaultValue,
+					Position: After,
+				},
+			},
+		},
+		{
+			desc:        "level offset",
+			levelOffset: 1,
+			text: texterFunc(func(i *HeaderInfo) string {
+				return strings.Repeat("#", i.Level)
+			}),
+			give: []string{
+				"# Foo",
+				"",
+				"## Bar",
+				"",
+				"###### Baz",
+			},
+			want: []*anchor{
+				{
+					ID:       "foo",
+					Level:    2,
+					Value:    "##",
+					Position: After,
+				},
+				{
+					ID:       "bar",
+					Level:    3,
+					Value:    "###",
+					Position: After,
+				},
+				{
+					ID:       "baz",
+					Level:    6,
+					Value:    "######",
+					Position: After,
+				},
+			},
+		},
+		{
+			desc:        "negative level offset",
+			levelOffset: -2,
+			give: []string{
+				"# Foo",
+				"",
+				"### Bar",
+			},
+			want: []*anchor{
+				{
+					ID:       "foo",
+					Level:    1,
+					Value:    defaultValue,
+					Position: After,
+				},
+				{
+					ID:       "bar",
+					Level:    1,
+					Value:    defaultValue,
+					Position: After,
+				},
+			},
+		},
 		{
 			desc: "no title yet",
 			give: []string{
@@ -147,8 +232,10 @@ func TestTransform(t *testing.T) {
 				parser.WithAutoHeadingID(),
 				parser.WithASTTransformers(
 					util.Prioritized(&Transformer{
-						Position: tt.pos,
-						Texter:   tt.text,
+						Position:    tt.pos,
+						Texter:      tt.text,
+						LinkPolicy:  tt.links,
+						LevelOffset: tt.levelOffset,
 					}, 100),
 				),
 			)
@@ -172,6 +259,8 @@ func TestTransform(t *testing.T) {
 					gotAnchors = append(gotAnchors, nil)
 					return ast.WalkSkipChildren, nil
 				}
+				assert.Equal(t, gotPos, an.Position,
+					"node position must match its placement")
 
 				gotAnchors = append(gotAnchors, &anchor{
 					ID:       string(an.ID),
@@ -210,6 +299,41 @@ func TestTransform_noHeadingIDs(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestTransform_headerInfoText(t *testing.T) {
+	t.Parallel()
+
+	var texts, slugs []string
+	p := goldmark.New().Parser()
+	p.AddOptions(
+		parser.WithAutoHeadingID(),
+		parser.WithASTTransformers(
+			util.Prioritized(&Transformer{
+				Emoji:     EmojiShortcode,
+				EmojiName: emojiname.Name,
+				Texter: texterFunc(func(i *HeaderInfo) string {
+					texts = append(texts, string(i.Text()))
+					slugs = append(slugs, string(i.SlugSource()))
+					return "#"
+				}),
+			}, 100),
+		),
+	)
+
+	src := []byte("# 🚀 Deploying **fast**\n\n## Use `go test`\n")
+	p.Parse(text.NewReader(src))
+
+	assert.Equal(t, []string{"rocket Deploying fast", "Use go test"}, texts)
+	assert.Equal(t, []string{"rocket Deploying fast", "Use go test"}, slugs)
+}
+
+func TestHeaderInfo_textEmpty(t *testing.T) {
+	t.Parallel()
+
+	info := HeaderInfo{Level: 1, ID: []byte("foo")}
+	assert.Nil(t, info.Text())
+	assert.Nil(t, info.SlugSource())
+}
+
 func TestTransform_badIDAttribute(t *testing.T) {
 	t.Parallel()
 
@@ -225,6 +349,229 @@ func TestTransform_badIDAttribute(t *testing.T) {
 	})
 }
 
+func TestTransform_twice(t *testing.T) {
+	t.Parallel()
+
+	p := goldmark.New().Parser()
+	p.AddOptions(
+		parser.WithAutoHeadingID(),
+		parser.WithASTTransformers(
+			util.Prioritized(&Transformer{}, 100),
+			util.Prioritized(&Transformer{
+				Texter:     Text("#"),
+				Position:   Before,
+				Attributer: Attributes{"class": "permalink"},
+			}, 200),
+		),
+	)
+
+	src := []byte("# Foo\n\n## Bar baz\n")
+	doc := p.Parse(text.NewReader(src))
+
+	var count int
+	err := ast.Walk(doc, func(n ast.Node, enter bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !enter || !ok {
+			return ast.WalkContinue, nil
+		}
+
+		an, pos := findAnchor(h)
+		require.NotNil(t, an)
+		count++
+
+		// The second transformer wins.
+		assert.Equal(t, "#", string(an.Value))
+		assert.Equal(t, Before, pos)
+		assert.Equal(t, Before, an.Position)
+		class, _ := an.AttributeString("class")
+		assert.Equal(t, []byte("permalink"), class)
+		_, ok = h.LastChild().(*Node)
+		assert.False(t, ok, "heading must not have a second anchor")
+		return ast.WalkSkipChildren, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestTransform_twiceWithPrefix(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithASTTransformers(
+				util.Prioritized(&Transformer{
+					IDPrefix:    "doc-",
+					LevelOffset: 1,
+				}, 100),
+				util.Prioritized(&Transformer{
+					Texter:      Text("#"),
+					IDPrefix:    "other-",
+					LevelOffset: 2,
+				}, 200),
+			),
+		),
+		goldmark.WithRendererOptions(
+			renderer.WithNodeRenderers(util.Prioritized(&Renderer{}, 100)),
+		),
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, md.Convert([]byte("# Foo\n"), &buf))
+
+	// The first IDPrefix and LevelOffset apply,
+	// and the second Transformer's anchor text wins.
+	assert.Equal(t,
+		`<h2 id="doc-foo">Foo <a class="anchor" href="#doc-foo">#</a></h2>`+"\n",
+		buf.String())
+
+	// A new conversion is transformed from scratch.
+	buf.Reset()
+	require.NoError(t, md.Convert([]byte("# Bar\n"), &buf))
+	assert.Equal(t,
+		`<h2 id="doc-bar">Bar <a class="anchor" href="#doc-bar">#</a></h2>`+"\n",
+		buf.String())
+}
+
+func TestTransform_retransform(t *testing.T) {
+	t.Parallel()
+
+	ctx := parser.NewContext()
+	src := []byte("# Foo\n\n## Bar\n")
+	md := goldmark.New(
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+	doc := md.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	tr := &Transformer{Texter: Text("#")}
+	tr.Transform(doc.(*ast.Document), text.NewReader(src), ctx)
+	first := Nodes(ctx)
+	tr.Transform(doc.(*ast.Document), text.NewReader(src), ctx)
+	second := Nodes(ctx)
+
+	require.Len(t, second, 2)
+	assert.Equal(t, first, second, "nodes must be reused")
+
+	var count int
+	err := ast.Walk(doc, func(n ast.Node, enter bool) (ast.WalkStatus, error) {
+		if _, ok := n.(*Node); ok && enter {
+			count++
+		}
+		return ast.WalkContinue, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestTransform_noWrapRetransform(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("# Foo Bar\n")
+	md := goldmark.New(
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithRendererOptions(
+			renderer.WithNodeRenderers(util.Prioritized(&Renderer{}, 100)),
+		),
+	)
+	doc := md.Parser().Parse(text.NewReader(src))
+
+	tests := []struct {
+		pos  Position
+		want string
+	}{
+		{
+			pos:  After,
+			want: `<h1 id="foo-bar">Foo <span style="white-space: nowrap">Bar <a href="#foo-bar">#</a></span></h1>`,
+		},
+		{
+			// Repeated transformations must not wrap the anchor again.
+			pos:  After,
+			want: `<h1 id="foo-bar">Foo <span style="white-space: nowrap">Bar <a href="#foo-bar">#</a></span></h1>`,
+		},
+		{
+			// Moving the anchor must undo the previous wrapping.
+			pos:  Before,
+			want: `<h1 id="foo-bar"><span style="white-space: nowrap"><a href="#foo-bar">#</a> Foo</span> Bar</h1>`,
+		},
+	}
+	for _, tt := range tests {
+		tr := &Transformer{
+			Texter:     Text("#"),
+			Attributer: Attributes{},
+			Position:   tt.pos,
+			NoWrap:     true,
+		}
+		tr.Transform(doc.(*ast.Document), text.NewReader(src), parser.NewContext())
+
+		var buf bytes.Buffer
+		require.NoError(t, md.Renderer().Render(&buf, src, doc))
+		assert.Equal(t, tt.want+"\n", buf.String(), "Position: %v", tt.pos)
+	}
+}
+
+func TestTransform_newNodeChunks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tr    transform
+		sizes []int
+	)
+	for range 8 + 16 + 32 + 64 + 128 + 256 + 1 {
+		allocate := len(tr.nodeChunk) == 0
+		tr.newNode()
+		if allocate {
+			sizes = append(sizes, tr.nodeChunkSize)
+		}
+	}
+	assert.Equal(t, []int{8, 16, 32, 64, 128, 256, 256}, sizes)
+}
+
+func TestTransform_attributeOrder(t *testing.T) {
+	t.Parallel()
+
+	attrs := map[string]string{"title": "a", "class": "b", "data-x": "c", "aria-label": "d", "id": "e"}
+	tests := []struct {
+		desc string
+		give Attributer
+	}{
+		{desc: "constant", give: Attributes(attrs)},
+		{
+			desc: "dynamic",
+			give: attributerFunc(func(*HeaderInfo) map[string]string {
+				return attrs
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := goldmark.New().Parser()
+			p.AddOptions(
+				parser.WithAutoHeadingID(),
+				parser.WithASTTransformers(
+					util.Prioritized(&Transformer{Attributer: tt.give}, 100),
+				),
+			)
+
+			src := []byte("# Foo\n\n## Bar\n")
+			ctx := parser.NewContext()
+			p.Parse(text.NewReader(src), parser.WithContext(ctx))
+
+			nodes := Nodes(ctx)
+			require.Len(t, nodes, 2)
+			for _, n := range nodes {
+				var names []string
+				for _, attr := range n.Attributes() {
+					names = append(names, string(attr.Name))
+				}
+				assert.Equal(t, []string{"aria-label", "class", "data-x", "id", "title"}, names)
+			}
+		})
+	}
+}
+
 func findAnchor(h *ast.Heading) (an *Node, pos Position) {
 	if an, ok := h.LastChild().(*Node); ok {
 		return an, After
@@ -240,3 +587,69 @@ type texterFunc func(*HeaderInfo) string
 func (f texterFunc) AnchorText(i *HeaderInfo) []byte {
 	return []byte(f(i))
 }
+
+func BenchmarkTransform(b *testing.B) {
+	const numHeadings = 1000
+
+	var src bytes.Buffer
+	for i := range numHeadings {
+		fmt.Fprintf(&src, "## Heading %d\n\nSome text.\n\n", i)
+	}
+
+	benchmarks := []struct {
+		name string
+		give *Transformer
+	}{
+		{name: "default", give: &Transformer{}},
+		{
+			name: "constant",
+			give: &Transformer{
+				Texter:     Text("#"),
+				Attributer: Attributes{"class": "anchor", "title": "Permalink"},
+			},
+		},
+		{
+			name: "dynamic",
+			give: &Transformer{
+				Texter: texterFunc(func(*HeaderInfo) string {
+					return "#"
+				}),
+				Attributer: attributerFunc(func(*HeaderInfo) map[string]string {
+					return map[string]string{"class": "anchor"}
+				}),
+			},
+		},
+	}
+
+	for _, bb := range benchmarks {
+		b.Run(bb.name, func(b *testing.B) {
+			p := goldmark.New(
+				goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+			).Parser()
+
+			b.ReportAllocs()
+			var mallocs uint64
+			for b.Loop() {
+				b.StopTimer()
+				doc := p.Parse(text.NewReader(src.Bytes()))
+				var before, after runtime.MemStats
+				runtime.ReadMemStats(&before)
+				b.StartTimer()
+
+				bb.give.Transform(doc.(*ast.Document), text.NewReader(src.Bytes()), nil)
+
+				b.StopTimer()
+				runtime.ReadMemStats(&after)
+				mallocs += after.Mallocs - before.Mallocs
+				b.StartTimer()
+			}
+			b.ReportMetric(float64(mallocs)/float64(b.N*numHeadings), "allocs/heading")
+		})
+	}
+}
+
+type attributerFunc func(*HeaderInfo) map[string]string
+
+func (f attributerFunc) AnchorAttributes(i *HeaderInfo) map[string]string {
+	return f(i)
+}
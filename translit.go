@@ -0,0 +1,642 @@
+package anchor
+
+// Transliterator maps non-ASCII characters to ASCII.
+//
+// Use it with [UnicodeSlugger] to generate ASCII-only identifiers
+// for headers written in non-Latin scripts.
+type Transliterator interface {
+	// Transliterate returns the ASCII replacement for the given
+	// lowercase character, and whether a replacement exists.
+	//
+	// The replacement may be empty to drop the character.
+	Transliterate(r rune) (string, bool)
+}
+
+// TransliterationTable is a [Transliterator]
+// backed by a map from lowercase characters to their replacements.
+//
+// Replacements that end with a space
+// separate the character from the next one with a '-' in identifiers.
+type TransliterationTable map[rune]string
+
+var _ Transliterator = TransliterationTable(nil)
+
+// Transliterate returns the replacement for r from the table.
+func (t TransliterationTable) Transliterate(r rune) (string, bool) {
+	s, ok := t[r]
+	return s, ok
+}
+
+// Transliterators combines multiple Transliterators into one.
+// Each character is transliterated by the first Transliterator
+// that has a replacement for it.
+//
+//	anchor.UnicodeSlugger{
+//		Transliterator: anchor.Transliterators(anchor.Cyrillic(), anchor.Greek()),
+//	}
+func Transliterators(ts ...Transliterator) Transliterator {
+	return multiTransliterator(ts)
+}
+
+// readOnlyTable is a Transliterator backed by a TransliterationTable
+// that isn't exposed to callers, so they can't modify it.
+type readOnlyTable struct{ table TransliterationTable }
+
+func (t readOnlyTable) Transliterate(r rune) (string, bool) {
+	return t.table.Transliterate(r)
+}
+
+type multiTransliterator []Transliterator
+
+func (ts multiTransliterator) Transliterate(r rune) (string, bool) {
+	for _, t := range ts {
+		if s, ok := t.Transliterate(r); ok {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// Cyrillic transliterates Russian, Ukrainian, Belarusian, Serbian,
+// and Macedonian Cyrillic letters to ASCII.
+//
+// To override entries, build a new TransliterationTable
+// and combine it with [Transliterators].
+func Cyrillic() Transliterator {
+	return readOnlyTable{_cyrillic}
+}
+
+var _cyrillic = TransliterationTable{
+	'а': "a",
+	'б': "b",
+	'в': "v",
+	'г': "g",
+	'д': "d",
+	'е': "e",
+	'ё': "yo",
+	'ж': "zh",
+	'з': "z",
+	'и': "i",
+	'й': "y",
+	'к': "k",
+	'л': "l",
+	'м': "m",
+	'н': "n",
+	'о': "o",
+	'п': "p",
+	'р': "r",
+	'с': "s",
+	'т': "t",
+	'у': "u",
+	'ф': "f",
+	'х': "kh",
+	'ц': "ts",
+	'ч': "ch",
+	'ш': "sh",
+	'щ': "shch",
+	'ъ': "",
+	'ы': "y",
+	'ь': "",
+	'э': "e",
+	'ю': "yu",
+	'я': "ya",
+	'є': "ye",
+	'і': "i",
+	'ї': "yi",
+	'ґ': "g",
+	'ў': "u",
+	'ђ': "dj",
+	'ј': "j",
+	'љ': "lj",
+	'њ': "nj",
+	'ћ': "c",
+	'џ': "dz",
+	'ѓ': "gj",
+	'ќ': "kj",
+	'ѕ': "dz",
+}
+
+// Greek transliterates Greek letters, including accented vowels, to ASCII.
+//
+// To override entries, build a new TransliterationTable
+// and combine it with [Transliterators].
+func Greek() Transliterator {
+	return readOnlyTable{_greek}
+}
+
+var _greek = TransliterationTable{
+	'α': "a",
+	'β': "v",
+	'γ': "g",
+	'δ': "d",
+	'ε': "e",
+	'ζ': "z",
+	'η': "i",
+	'θ': "th",
+	'ι': "i",
+	'κ': "k",
+	'λ': "l",
+	'μ': "m",
+	'ν': "n",
+	'ξ': "x",
+	'ο': "o",
+	'π': "p",
+	'ρ': "r",
+	'σ': "s",
+	'ς': "s",
+	'τ': "t",
+	'υ': "y",
+	'φ': "f",
+	'χ': "ch",
+	'ψ': "ps",
+	'ω': "o",
+	'ά': "a",
+	'έ': "e",
+	'ή': "i",
+	'ί': "i",
+	'ό': "o",
+	'ύ': "y",
+	'ώ': "o",
+	'ϊ': "i",
+	'ϋ': "y",
+	'ΐ': "i",
+	'ΰ': "y",
+}
+
+// Pinyin transliterates common Simplified Chinese characters
+// to toneless Hanyu Pinyin, separating syllables with '-'.
+// It covers the most frequently used characters,
+// and characters common in technical documentation.
+//
+// To add characters, build a new TransliterationTable
+// and combine it with [Transliterators].
+func Pinyin() Transliterator {
+	return readOnlyTable{_pinyin}
+}
+
+var _pinyin = TransliterationTable{
+	'一': "yi ",
+	'七': "qi ",
+	'万': "wan ",
+	'三': "san ",
+	'上': "shang ",
+	'下': "xia ",
+	'不': "bu ",
+	'与': "yu ",
+	'世': "shi ",
+	'业': "ye ",
+	'东': "dong ",
+	'两': "liang ",
+	'个': "ge ",
+	'中': "zhong ",
+	'为': "wei ",
+	'主': "zhu ",
+	'么': "me ",
+	'义': "yi ",
+	'之': "zhi ",
+	'九': "jiu ",
+	'也': "ye ",
+	'书': "shu ",
+	'了': "le ",
+	'事': "shi ",
+	'二': "er ",
+	'于': "yu ",
+	'五': "wu ",
+	'些': "xie ",
+	'交': "jiao ",
+	'产': "chan ",
+	'京': "jing ",
+	'亲': "qin ",
+	'人': "ren ",
+	'什': "shen ",
+	'今': "jin ",
+	'介': "jie ",
+	'从': "cong ",
+	'他': "ta ",
+	'代': "dai ",
+	'令': "ling ",
+	'以': "yi ",
+	'们': "men ",
+	'件': "jian ",
+	'任': "ren ",
+	'众': "zhong ",
+	'会': "hui ",
+	'传': "chuan ",
+	'但': "dan ",
+	'位': "wei ",
+	'住': "zhu ",
+	'体': "ti ",
+	'何': "he ",
+	'作': "zuo ",
+	'你': "ni ",
+	'使': "shi ",
+	'例': "li ",
+	'便': "bian ",
+	'保': "bao ",
+	'信': "xin ",
+	'候': "hou ",
+	'值': "zhi ",
+	'做': "zuo ",
+	'儿': "er ",
+	'元': "yuan ",
+	'先': "xian ",
+	'光': "guang ",
+	'入': "ru ",
+	'全': "quan ",
+	'八': "ba ",
+	'公': "gong ",
+	'六': "liu ",
+	'共': "gong ",
+	'关': "guan ",
+	'其': "qi ",
+	'具': "ju ",
+	'内': "nei ",
+	'册': "ce ",
+	'再': "zai ",
+	'写': "xie ",
+	'军': "jun ",
+	'决': "jue ",
+	'几': "ji ",
+	'出': "chu ",
+	'函': "han ",
+	'分': "fen ",
+	'列': "lie ",
+	'创': "chuang ",
+	'删': "shan ",
+	'利': "li ",
+	'别': "bie ",
+	'到': "dao ",
+	'制': "zhi ",
+	'前': "qian ",
+	'力': "li ",
+	'办': "ban ",
+	'功': "gong ",
+	'加': "jia ",
+	'务': "wu ",
+	'动': "dong ",
+	'化': "hua ",
+	'北': "bei ",
+	'区': "qu ",
+	'十': "shi ",
+	'半': "ban ",
+	'华': "hua ",
+	'南': "nan ",
+	'即': "ji ",
+	'却': "que ",
+	'原': "yuan ",
+	'去': "qu ",
+	'参': "can ",
+	'又': "you ",
+	'及': "ji ",
+	'反': "fan ",
+	'发': "fa ",
+	'受': "shou ",
+	'变': "bian ",
+	'口': "kou ",
+	'只': "zhi ",
+	'叫': "jiao ",
+	'可': "ke ",
+	'台': "tai ",
+	'号': "hao ",
+	'司': "si ",
+	'吃': "chi ",
+	'各': "ge ",
+	'合': "he ",
+	'同': "tong ",
+	'名': "ming ",
+	'后': "hou ",
+	'向': "xiang ",
+	'听': "ting ",
+	'告': "gao ",
+	'员': "yuan ",
+	'呢': "ne ",
+	'周': "zhou ",
+	'命': "ming ",
+	'和': "he ",
+	'品': "pin ",
+	'器': "qi ",
+	'四': "si ",
+	'回': "hui ",
+	'因': "yin ",
+	'国': "guo ",
+	'在': "zai ",
+	'地': "di ",
+	'场': "chang ",
+	'块': "kuai ",
+	'型': "xing ",
+	'城': "cheng ",
+	'基': "ji ",
+	'境': "jing ",
+	'士': "shi ",
+	'声': "sheng ",
+	'处': "chu ",
+	'外': "wai ",
+	'多': "duo ",
+	'大': "da ",
+	'天': "tian ",
+	'太': "tai ",
+	'夫': "fu ",
+	'头': "tou ",
+	'女': "nv ",
+	'她': "ta ",
+	'好': "hao ",
+	'如': "ru ",
+	'子': "zi ",
+	'字': "zi ",
+	'学': "xue ",
+	'它': "ta ",
+	'安': "an ",
+	'完': "wan ",
+	'定': "ding ",
+	'实': "shi ",
+	'客': "ke ",
+	'家': "jia ",
+	'密': "mi ",
+	'对': "dui ",
+	'将': "jiang ",
+	'小': "xiao ",
+	'少': "shao ",
+	'就': "jiu ",
+	'展': "zhan ",
+	'山': "shan ",
+	'工': "gong ",
+	'己': "ji ",
+	'已': "yi ",
+	'市': "shi ",
+	'师': "shi ",
+	'带': "dai ",
+	'常': "chang ",
+	'干': "gan ",
+	'平': "ping ",
+	'年': "nian ",
+	'并': "bing ",
+	'库': "ku ",
+	'应': "ying ",
+	'度': "du ",
+	'建': "jian ",
+	'开': "kai ",
+	'式': "shi ",
+	'引': "yin ",
+	'张': "zhang ",
+	'强': "qiang ",
+	'当': "dang ",
+	'录': "lu ",
+	'往': "wang ",
+	'很': "hen ",
+	'得': "de ",
+	'心': "xin ",
+	'必': "bi ",
+	'志': "zhi ",
+	'快': "kuai ",
+	'怎': "zen ",
+	'思': "si ",
+	'性': "xing ",
+	'总': "zong ",
+	'息': "xi ",
+	'情': "qing ",
+	'想': "xiang ",
+	'意': "yi ",
+	'感': "gan ",
+	'成': "cheng ",
+	'我': "wo ",
+	'或': "huo ",
+	'战': "zhan ",
+	'户': "hu ",
+	'所': "suo ",
+	'手': "shou ",
+	'才': "cai ",
+	'打': "da ",
+	'技': "ji ",
+	'把': "ba ",
+	'报': "bao ",
+	'持': "chi ",
+	'指': "zhi ",
+	'据': "ju ",
+	'接': "jie ",
+	'提': "ti ",
+	'支': "zhi ",
+	'放': "fang ",
+	'政': "zheng ",
+	'教': "jiao ",
+	'数': "shu ",
+	'文': "wen ",
+	'新': "xin ",
+	'方': "fang ",
+	'无': "wu ",
+	'日': "ri ",
+	'时': "shi ",
+	'明': "ming ",
+	'是': "shi ",
+	'更': "geng ",
+	'最': "zui ",
+	'月': "yue ",
+	'有': "you ",
+	'服': "fu ",
+	'望': "wang ",
+	'期': "qi ",
+	'本': "ben ",
+	'术': "shu ",
+	'机': "ji ",
+	'权': "quan ",
+	'李': "li ",
+	'条': "tiao ",
+	'来': "lai ",
+	'极': "ji ",
+	'构': "gou ",
+	'林': "lin ",
+	'果': "guo ",
+	'查': "cha ",
+	'样': "yang ",
+	'格': "ge ",
+	'档': "dang ",
+	'概': "gai ",
+	'模': "mo ",
+	'次': "ci ",
+	'正': "zheng ",
+	'此': "ci ",
+	'步': "bu ",
+	'武': "wu ",
+	'死': "si ",
+	'每': "mei ",
+	'比': "bi ",
+	'毛': "mao ",
+	'民': "min ",
+	'气': "qi ",
+	'水': "shui ",
+	'求': "qiu ",
+	'江': "jiang ",
+	'没': "mei ",
+	'治': "zhi ",
+	'法': "fa ",
+	'注': "zhu ",
+	'活': "huo ",
+	'派': "pai ",
+	'流': "liu ",
+	'测': "ce ",
+	'海': "hai ",
+	'消': "xiao ",
+	'深': "shen ",
+	'清': "qing ",
+	'满': "man ",
+	'点': "dian ",
+	'然': "ran ",
+	'爱': "ai ",
+	'父': "fu ",
+	'版': "ban ",
+	'物': "wu ",
+	'特': "te ",
+	'献': "xian ",
+	'王': "wang ",
+	'环': "huan ",
+	'现': "xian ",
+	'理': "li ",
+	'甚': "shen ",
+	'生': "sheng ",
+	'用': "yong ",
+	'由': "you ",
+	'电': "dian ",
+	'界': "jie ",
+	'留': "liu ",
+	'登': "deng ",
+	'白': "bai ",
+	'百': "bai ",
+	'的': "de ",
+	'目': "mu ",
+	'直': "zhi ",
+	'相': "xiang ",
+	'看': "kan ",
+	'真': "zhen ",
+	'眼': "yan ",
+	'着': "zhe ",
+	'知': "zhi ",
+	'码': "ma ",
+	'示': "shi ",
+	'社': "she ",
+	'神': "shen ",
+	'种': "zhong ",
+	'科': "ke ",
+	'程': "cheng ",
+	'空': "kong ",
+	'立': "li ",
+	'端': "duan ",
+	'笑': "xiao ",
+	'第': "di ",
+	'等': "deng ",
+	'简': "jian ",
+	'算': "suan ",
+	'管': "guan ",
+	'类': "lei ",
+	'系': "xi ",
+	'索': "suo ",
+	'红': "hong ",
+	'经': "jing ",
+	'结': "jie ",
+	'给': "gei ",
+	'络': "luo ",
+	'统': "tong ",
+	'编': "bian ",
+	'网': "wang ",
+	'置': "zhi ",
+	'署': "shu ",
+	'美': "mei ",
+	'老': "lao ",
+	'考': "kao ",
+	'者': "zhe ",
+	'而': "er ",
+	'联': "lian ",
+	'能': "neng ",
+	'自': "zi ",
+	'至': "zhi ",
+	'色': "se ",
+	'花': "hua ",
+	'英': "ying ",
+	'行': "xing ",
+	'表': "biao ",
+	'被': "bei ",
+	'装': "zhuang ",
+	'西': "xi ",
+	'要': "yao ",
+	'见': "jian ",
+	'觉': "jue ",
+	'解': "jie ",
+	'言': "yan ",
+	'计': "ji ",
+	'认': "ren ",
+	'让': "rang ",
+	'记': "ji ",
+	'许': "xu ",
+	'论': "lun ",
+	'设': "she ",
+	'证': "zheng ",
+	'识': "shi ",
+	'译': "yi ",
+	'试': "shi ",
+	'话': "hua ",
+	'询': "xun ",
+	'该': "gai ",
+	'语': "yu ",
+	'误': "wu ",
+	'说': "shuo ",
+	'请': "qing ",
+	'调': "tiao ",
+	'象': "xiang ",
+	'贡': "gong ",
+	'账': "zhang ",
+	'资': "zi ",
+	'走': "zou ",
+	'起': "qi ",
+	'跟': "gen ",
+	'路': "lu ",
+	'身': "shen ",
+	'车': "che ",
+	'转': "zhuan ",
+	'轻': "qing ",
+	'载': "zai ",
+	'输': "shu ",
+	'边': "bian ",
+	'达': "da ",
+	'过': "guo ",
+	'运': "yun ",
+	'近': "jin ",
+	'返': "fan ",
+	'还': "hai ",
+	'这': "zhe ",
+	'进': "jin ",
+	'远': "yuan ",
+	'连': "lian ",
+	'述': "shu ",
+	'选': "xuan ",
+	'通': "tong ",
+	'速': "su ",
+	'道': "dao ",
+	'那': "na ",
+	'部': "bu ",
+	'都': "dou ",
+	'配': "pei ",
+	'里': "li ",
+	'重': "zhong ",
+	'量': "liang ",
+	'金': "jin ",
+	'错': "cuo ",
+	'长': "chang ",
+	'门': "men ",
+	'问': "wen ",
+	'间': "jian ",
+	'队': "dui ",
+	'院': "yuan ",
+	'除': "chu ",
+	'难': "nan ",
+	'青': "qing ",
+	'非': "fei ",
+	'面': "mian ",
+	'革': "ge ",
+	'页': "ye ",
+	'项': "xiang ",
+	'领': "ling ",
+	'题': "ti ",
+	'风': "feng ",
+	'首': "shou ",
+	'马': "ma ",
+	'高': "gao ",
+	'黑': "hei ",
+}